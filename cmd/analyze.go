@@ -5,25 +5,14 @@ import (
 	"fmt"
 	"log"
 	"strings"
-	"time"
 
 	"github.com/newman-bot/kfin/pkg/config"
-	"github.com/newman-bot/kfin/pkg/pdf"
-	"github.com/newman-bot/kfin/pkg/pricing"
-	"github.com/newman-bot/kfin/pkg/tui"
+	"github.com/newman-bot/kfin/pkg/cost"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
-const nodeInstanceTypeLabel = "node.kubernetes.io/instance-type"
-
 var cfg *config.Config
-var activeUsageRates pricing.UsageRates
-var activeUsageRatesSource = "config"
 
 func init() {
 	// Try to load config, fall back to defaults
@@ -32,277 +21,349 @@ func init() {
 	if err != nil {
 		cfg = config.DefaultConfig()
 	}
-	activeUsageRates = pricing.UsageRates{
-		CPUPerHour:   cfg.Pricing.Cloud.CPUPerHour,
-		MemPerGBHour: cfg.Pricing.Cloud.MemPerGBHour,
-	}
 }
 
 func AnalyzeCmd() *cobra.Command {
-	return &cobra.Command{
+	opts := defaultReportOptions()
+	analyzeCmd := &cobra.Command{
 		Use:   "analyze",
 		Short: "Analyze pod costs in the cluster",
 		Run: func(cmd *cobra.Command, args []string) {
-			analyzeCluster()
+			analyzeCluster(opts)
 		},
 	}
+	addReportFlags(analyzeCmd, &opts)
+	addGroupByFlag(analyzeCmd, &opts)
+	addChargebackFlag(analyzeCmd, &opts)
+	return analyzeCmd
 }
 
-func analyzeCluster() {
-	// Load kubeconfig
-	kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		&clientcmd.ClientConfigLoadingRules{ExplicitPath: clientcmd.RecommendedHomeFile},
-		&clientcmd.ConfigOverrides{},
-	)
-
-	k8sConfig, err := kubeconfig.ClientConfig()
+func analyzeCluster(opts reportOptions) {
+	dims, err := groupDimensions(opts)
 	if err != nil {
-		log.Fatalf("Failed to load kubeconfig: %v", err)
+		log.Fatalf("Invalid --group-by: %v", err)
 	}
-
-	// Create clientset
-	clientset, err := kubernetes.NewForConfig(k8sConfig)
+	reports, err := buildClusterReports(context.Background(), opts)
 	if err != nil {
-		log.Fatalf("Failed to create kubernetes client: %v", err)
+		log.Fatalf("Failed to build cost report: %v", err)
 	}
-
-	ctx := context.Background()
-
-	// List all pods across all namespaces
-	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		log.Fatalf("Failed to list pods: %v", err)
+	if len(reports) == 1 {
+		printReport(reports[0].Report)
+		if dims != nil {
+			printGroups(reports[0].Report, dims, "")
+		}
+		printChargeback(reports[0].Report.Chargeback, "")
+		return
 	}
-
-	// Get nodes for hardware cost calculation
-	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		log.Fatalf("Failed to list nodes: %v", err)
+	printFleet(cost.NewFleet(reports))
+	for _, r := range reports {
+		if dims != nil {
+			printGroups(r.Report, dims, r.Context)
+		}
+		printChargeback(r.Report.Chargeback, r.Context)
 	}
+}
 
-	resolveUsageRates()
-
-	// Calculate total hardware costs
-	hardwareCostMonthly, electricityCostMonthly, controlPlaneMonthly := calculateClusterCosts(nodes.Items)
-	totalMonthly := hardwareCostMonthly + electricityCostMonthly + controlPlaneMonthly
-
-	fmt.Printf("Found %d pods across %d nodes\n\n", len(pods.Items), len(nodes.Items))
+func printReport(report *cost.Report) {
+	fmt.Printf("Found %d pods across %d nodes\n\n", len(report.Pods), len(report.Nodes))
 
 	// Print cost summary
 	fmt.Printf("=== Monthly Cost Summary ===\n")
-	fmt.Printf("Hardware (amortized): $%.2f\n", hardwareCostMonthly)
-	fmt.Printf("Electricity:         $%.2f\n", electricityCostMonthly)
-	fmt.Printf("EKS control plane:   $%.2f\n", controlPlaneMonthly)
-	fmt.Printf("Total:               $%.2f\n", totalMonthly)
-	fmt.Printf("Pod pricing source:  %s (cpu_per_hour=%.6f, mem_per_gb_hour=%.6f)\n\n",
-		activeUsageRatesSource, activeUsageRates.CPUPerHour, activeUsageRates.MemPerGBHour)
-
-	fmt.Printf("%-40s %-15s %-12s %-12s %-12s\n", "POD", "NAMESPACE", "CPU REQ", "MEM REQ", "MONTHLY $")
-	fmt.Println("================================================================================")
-
-	var totalCPU, totalMem resource.Quantity
-
-	for _, pod := range pods.Items {
-		for _, container := range pod.Spec.Containers {
-			cpu := container.Resources.Requests.Cpu()
-			mem := container.Resources.Requests.Memory()
-
-			// Add to totals
-			totalCPU.Add(*cpu)
-			totalMem.Add(*mem)
-
-			// Calculate cost for this container
-			cost := calculateContainerCost(cpu, mem)
-
-			// Only show containers with requests
-			if cost > 0 {
-				fmt.Printf("%-40s %-15s %-12s %-12s $%-11.2f\n",
-					truncate(container.Name, 40),
-					pod.Namespace,
-					cpu.String(),
-					mem.String(),
-					cost)
-			}
+	fmt.Printf("Hardware (amortized): $%.2f\n", report.HardwareCost)
+	fmt.Printf("Electricity:         $%.2f\n", report.ElecCost)
+	fmt.Printf("Control plane:       $%.2f (%s)\n", report.ControlPlaneCost, report.Distribution)
+	fmt.Printf("Storage:             $%.2f\n", report.StorageCost)
+	if report.StorageWasteCost > 0 {
+		fmt.Printf("  of which unbound:  $%.2f\n", report.StorageWasteCost)
+	}
+	fmt.Printf("Load balancers:      $%.2f\n", report.LoadBalancerCost)
+	fmt.Printf("Network:             $%.2f\n", report.NetworkCost)
+	if report.FargatePods > 0 {
+		fmt.Printf("EKS Fargate:         $%.2f (%d pods)\n", report.FargateCost, report.FargatePods)
+	}
+	fmt.Printf("Total:               $%.2f\n", report.TotalCost)
+	fmt.Printf("  of which idle:     $%.2f\n", report.IdleCost)
+	if report.SpotNodes > 0 {
+		fmt.Printf("Spot nodes:          %d/%d (%.0f%%), saving $%.2f vs on-demand\n",
+			report.SpotNodes, report.InstanceNodes, report.SpotShare*100, report.SpotSavings)
+	}
+	fmt.Printf("Pod pricing source:  %s (cpu_per_hour=%.6f, mem_per_gb_hour=%.6f)\n",
+		report.PricingSource, report.Rates.CPUPerHour, report.Rates.MemPerGBHour)
+	fmt.Printf("Pod cost basis:      %s\n", report.CostBasis)
+	if report.Allocation == cost.AllocationNodeCost {
+		fmt.Printf("Pod cost allocation: %s (cpu:memory %.2f:1)\n", report.Allocation, report.CPUMemoryRatio)
+	} else {
+		fmt.Printf("Pod cost allocation: %s\n", report.Allocation)
+	}
+	if report.Projected {
+		fmt.Printf("Pod runtime:         prorated over %s, running pods projected to month end\n", report.Period.Start.Format("January 2006"))
+	} else if report.Prorated {
+		fmt.Printf("Pod runtime:         prorated over %s, month to date\n", report.Period.Start.Format("January 2006"))
+	}
+	if report.TerminatedExcluded > 0 {
+		fmt.Printf("                     %d terminated pods excluded (use --include-terminated to bill them)\n", report.TerminatedExcluded)
+	}
+	if report.UsageMissing > 0 {
+		fmt.Printf("                     %d pods had no usage samples and were billed on requests\n", report.UsageMissing)
+	}
+	fmt.Println()
+
+	extHeader := extendedHeader(report.ExtendedResources)
+	fmt.Printf("%-40s %-15s %-12s %-12s %s%-12s\n", "POD", "NAMESPACE", "CPU", "MEM", extHeader, "MONTHLY $")
+	fmt.Println("================================================================================" + strings.Repeat("=", len(extHeader)))
+
+	extTotal := corev1.ResourceList{}
+	for _, pod := range report.Pods {
+		// Only show pods with requests
+		if pod.Cost > 0 {
+			fmt.Printf("%-40s %-15s %-12s %-12s %s$%-11.2f\n",
+				truncate(pod.Name, 40),
+				pod.Namespace,
+				pod.CPU.String(),
+				pod.Memory.String(),
+				extendedCells(report.ExtendedResources, pod.Extended),
+				pod.Cost)
+		}
+		for name, q := range pod.Extended {
+			total := extTotal[name]
+			total.Add(q)
+			extTotal[name] = total
 		}
 	}
 
-	fmt.Println("================================================================================")
-	totalCost := calculateContainerCost(&totalCPU, &totalMem)
-	fmt.Printf("%-40s %-15s %-12s %-12s $%-11.2f\n",
-		"TOTAL", "", totalCPU.String(), totalMem.String(), totalCost)
+	fmt.Println("================================================================================" + strings.Repeat("=", len(extHeader)))
+	fmt.Printf("%-40s %-15s %-12s %-12s %s$%-11.2f\n",
+		"TOTAL", "", report.RequestedCPU.String(), report.RequestedMemory.String(),
+		extendedCells(report.ExtendedResources, extTotal), report.PodTotalCost)
+
+	// Per-namespace rollup
+	fmt.Printf("\n=== Namespace Costs (monthly) ===\n")
+	fmt.Printf("%-40s %-20s %-6s %s%-12s\n", "NAMESPACE", "OWNER", "PODS", extHeader, "MONTHLY $")
+	var nsTotal float64
+	for _, ns := range report.Namespaces {
+		if ns.Cost > 0 {
+			fmt.Printf("%-40s %-20s %-6d %s$%-11.2f\n", truncate(ns.Name, 40), truncate(orDash(ns.Owner), 20), ns.Pods,
+				extendedCells(report.ExtendedResources, ns.Extended), ns.Cost)
+		}
+		nsTotal += ns.Cost
+	}
+	fmt.Printf("%-40s %-20s %-6s %s$%-11.2f\n", "TOTAL", "", "", extendedCells(report.ExtendedResources, extTotal), nsTotal)
+
+	// Per-workload rollup
+	fmt.Printf("\n=== Workload Costs (monthly) ===\n")
+	fmt.Printf("%-40s %-15s %-6s %-12s\n", "WORKLOAD", "NAMESPACE", "PODS", "MONTHLY $")
+	for _, w := range report.Workloads {
+		if w.Cost > 0 {
+			fmt.Printf("%-40s %-15s %-6d $%-11.2f\n",
+				truncate(w.String(), 40),
+				w.Namespace,
+				w.Pods,
+				w.Cost)
+		}
+	}
 
 	// Per-node breakdown
 	fmt.Printf("\n=== Node Hardware Costs (monthly) ===\n")
-	for _, node := range nodes.Items {
-		nodeCost, instanceType, usedInstanceOverride := calculateNodeHardwareCost(node)
-		elecCost := cfg.Pricing.WattsPerNode / 1000.0 * 730 * cfg.Pricing.ElectricityRate
-		if usedInstanceOverride {
-			fmt.Printf("%s (%s): $%.2f (hardware) + $%.2f (electricity) = $%.2f/month\n",
-				node.Name, instanceType, nodeCost, elecCost, nodeCost+elecCost)
+	for _, node := range report.Nodes {
+		if node.CapacityType == cost.CapacityFargate {
+			fmt.Printf("%s (fargate): billed per pod\n", node.Name)
 			continue
 		}
-		fmt.Printf("%s: $%.2f (hardware) + $%.2f (electricity) = $%.2f/month\n",
-			node.Name, nodeCost, elecCost, nodeCost+elecCost)
+		power := fmt.Sprintf("%.1fW %s", node.Watts, node.PowerSource)
+		if node.HardwareProfile != "" {
+			fmt.Printf("%s (profile %s): $%.2f (hardware) + $%.2f (electricity, %s) = $%.2f/month\n",
+				node.Name, node.HardwareProfile, node.HardwareCost, node.ElecCost, power, node.TotalCost)
+			continue
+		}
+		if node.InstanceOverride {
+			instance := node.InstanceType
+			if node.CapacityType != "" {
+				instance += " " + node.CapacityType
+			}
+			if node.Zone != "" {
+				instance += " " + node.Zone
+			}
+			fmt.Printf("%s (%s): $%.2f (hardware) + $%.2f (electricity, %s) = $%.2f/month\n",
+				node.Name, instance, node.HardwareCost, node.ElecCost, power, node.TotalCost)
+			continue
+		}
+		fmt.Printf("%s: $%.2f (hardware) + $%.2f (electricity, %s) = $%.2f/month\n",
+			node.Name, node.HardwareCost, node.ElecCost, power, node.TotalCost)
 	}
-}
 
-func calculateNodeHardwareCost(node corev1.Node) (float64, string, bool) {
-	instanceType := node.Labels[nodeInstanceTypeLabel]
-	if instanceType != "" {
-		if monthly, ok := cfg.Pricing.InstanceMonthlyByType[instanceType]; ok && monthly > 0 {
-			return monthly, instanceType, true
+	// Persistent volumes
+	if len(report.Volumes) > 0 {
+		fmt.Printf("\n=== Persistent Volumes (monthly) ===\n")
+		fmt.Printf("%-40s %-15s %-14s %-10s %-12s\n", "VOLUME", "NAMESPACE", "CLASS", "SIZE", "MONTHLY $")
+		for _, v := range report.Volumes {
+			name := v.Claim
+			if v.Waste {
+				name = v.Name + " (" + strings.ToLower(v.Phase) + ")"
+			}
+			fmt.Printf("%-40s %-15s %-14s %-10s $%-11.2f\n",
+				truncate(name, 40),
+				v.Namespace,
+				truncate(v.StorageClass, 14),
+				v.Capacity.String(),
+				v.Cost)
 		}
+		fmt.Printf("%-40s %-15s %-14s %-10s $%-11.2f\n", "TOTAL", "", "", "", report.StorageCost)
 	}
 
-	memGB := float64(node.Status.Capacity.Memory().Value()) / (1024 * 1024 * 1024)
-	return memGB * cfg.Pricing.HardwareMonthlyPerGB, instanceType, false
-}
-
-func calculateContainerCost(cpu *resource.Quantity, mem *resource.Quantity) float64 {
-	cpuCores := float64(cpu.MilliValue()) / 1000.0
-	memGB := float64(mem.Value()) / (1024 * 1024 * 1024)
-
-	monthlyCPUCost := cpuCores * 730 * activeUsageRates.CPUPerHour
-	monthlyMemCost := memGB * 730 * activeUsageRates.MemPerGBHour
-
-	return monthlyCPUCost + monthlyMemCost
-}
-
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+	// Load balancers
+	if len(report.LoadBalancers) > 0 {
+		fmt.Printf("\n=== Load Balancers (monthly) ===\n")
+		fmt.Printf("%-40s %-15s %-10s %-10s %-12s\n", "NAME", "NAMESPACE", "KIND", "TYPE", "MONTHLY $")
+		for _, lb := range report.LoadBalancers {
+			lbType := lb.Type
+			if lbType == "" {
+				lbType = "-"
+			}
+			fmt.Printf("%-40s %-15s %-10s %-10s $%-11.2f\n",
+				truncate(lb.Name, 40), lb.Namespace, lb.Kind, lbType, lb.Cost)
+		}
+		fmt.Printf("%-40s %-15s %-10s %-10s $%-11.2f\n", "TOTAL", "", "", "", report.LoadBalancerCost)
 	}
-	return s[:maxLen]
-}
-
-func collectPodCosts(pods []corev1.Pod) []tui.PodInfo {
-	var result []tui.PodInfo
-	for _, pod := range pods {
-		for _, container := range pod.Spec.Containers {
-			cpu := container.Resources.Requests.Cpu()
-			mem := container.Resources.Requests.Memory()
-			cost := calculateContainerCost(cpu, mem)
 
-			result = append(result, tui.PodInfo{
-				Name:      container.Name,
-				Namespace: pod.Namespace,
-				CPU:       cpu.String(),
-				Memory:    mem.String(),
-				Cost:      cost,
-			})
-		}
+	// Unrequested node capacity
+	fmt.Printf("\n=== Idle Capacity (monthly) ===\n")
+	fmt.Printf("%-30s %-16s %-16s %-12s\n", "NODE", "IDLE CPU", "IDLE MEM", "IDLE $")
+	for _, node := range report.Nodes {
+		fmt.Printf("%-30s %-16s %-16s $%-11.2f\n",
+			truncate(node.Name, 30),
+			node.IdleCPU.String()+"/"+node.AllocatableCPU.String(),
+			node.IdleMemory.String()+"/"+node.AllocatableMemory.String(),
+			node.IdleCost)
 	}
-	return result
+	fmt.Printf("%-30s %-16s %-16s $%-11.2f\n", "TOTAL", "", "", report.IdleCost)
 }
 
-func collectPodCostsForPdf(pods []corev1.Pod) []pdf.PodCost {
-	var result []pdf.PodCost
-	for _, pod := range pods {
-		for _, container := range pod.Spec.Containers {
-			cpu := container.Resources.Requests.Cpu()
-			mem := container.Resources.Requests.Memory()
-			cost := calculateContainerCost(cpu, mem)
+// printGroups prints the report's cost grouped by dims, one column per
+// dimension. contextName names the cluster in fleet output.
+func printGroups(report *cost.Report, dims []cost.GroupDimension, contextName string) {
+	names := make([]string, len(dims))
+	header := ""
+	for i, d := range dims {
+		names[i] = d.String()
+		header += fmt.Sprintf("%-30s ", truncate(strings.ToUpper(names[i]), 30))
+	}
+	scope := "monthly"
+	if contextName != "" {
+		scope += ", " + contextName
+	}
+	fmt.Printf("\n=== Costs by %s (%s) ===\n", strings.Join(names, ", "), scope)
+	fmt.Printf("%s%-6s %-12s\n", header, "PODS", "MONTHLY $")
 
-			result = append(result, pdf.PodCost{
-				Name:      container.Name,
-				Namespace: pod.Namespace,
-				CPU:       cpu.String(),
-				Memory:    mem.String(),
-				Cost:      cost,
-			})
+	var total float64
+	for _, g := range cost.GroupCosts(report, dims) {
+		total += g.Cost
+		if g.Cost <= 0 {
+			continue
+		}
+		row := ""
+		for _, k := range g.Keys {
+			row += fmt.Sprintf("%-30s ", truncate(k, 30))
 		}
+		fmt.Printf("%s%-6d $%-11.2f\n", row, g.Pods, g.Cost)
 	}
-	return result
+	fmt.Printf("%-*s%-6s $%-11.2f\n", 31*len(dims), "TOTAL", "", total)
 }
 
-func collectNodeInfo(nodes []corev1.Node) []tui.NodeInfo {
-	var result []tui.NodeInfo
-	for _, node := range nodes {
-		memGB := float64(node.Status.Capacity.Memory().Value()) / (1024 * 1024 * 1024)
-		hardwareCost, _, _ := calculateNodeHardwareCost(node)
-		elecCost := cfg.Pricing.WattsPerNode / 1000.0 * 730 * cfg.Pricing.ElectricityRate
-
-		result = append(result, tui.NodeInfo{
-			Name:         node.Name,
-			MemoryGB:     memGB,
-			HardwareCost: hardwareCost,
-			ElecCost:     elecCost,
-			TotalCost:    hardwareCost + elecCost,
-		})
-	}
-	return result
+// printChargeback prints each tenant's direct cost, share of the shared pool
+// and invoice total. A nil chargeback prints nothing.
+func printChargeback(cb *cost.Chargeback, contextName string) {
+	if cb == nil {
+		return
+	}
+	scope := "monthly, " + cb.Method
+	if contextName != "" {
+		scope += ", " + contextName
+	}
+	fmt.Printf("\n=== Chargeback (%s) ===\n", scope)
+	shared := "none"
+	if len(cb.SharedNamespaces) > 0 {
+		shared = strings.Join(cb.SharedNamespaces, ", ")
+	}
+	fmt.Printf("Shared pool: $%.2f (namespaces %s: $%.2f, idle: $%.2f, control plane: $%.2f, unallocated: $%.2f)\n",
+		cb.SharedCost, shared, cb.SharedNamespaceCost, cb.IdleCost, cb.ControlPlaneCost, cb.UnallocatedCost)
+	if cb.OverAllocatedCost > 0 {
+		fmt.Printf("Over-allocated: $%.2f (pod prices above node cost, scaled out of direct cost)\n", cb.OverAllocatedCost)
+	}
+	if len(cb.Tenants) == 0 {
+		fmt.Printf("No tenant namespaces to charge back\n")
+		return
+	}
+	fmt.Printf("%-40s %-12s %-12s %-12s\n", "TENANT", "DIRECT $", "SHARED $", "TOTAL $")
+	for _, t := range cb.Tenants {
+		fmt.Printf("%-40s $%-11.2f $%-11.2f $%-11.2f\n", truncate(t.Namespace, 40), t.Direct, t.Shared, t.Total)
+	}
+	fmt.Printf("%-40s %-12s %-12s $%-11.2f\n", "TOTAL", "", "", cb.TotalCost)
 }
 
-func collectNodeInfoForPdf(nodes []corev1.Node) []pdf.NodeInfo {
-	var result []pdf.NodeInfo
-	for _, node := range nodes {
-		memGB := float64(node.Status.Capacity.Memory().Value()) / (1024 * 1024 * 1024)
-		hardwareCost, _, _ := calculateNodeHardwareCost(node)
-		elecCost := cfg.Pricing.WattsPerNode / 1000.0 * 730 * cfg.Pricing.ElectricityRate
-
-		result = append(result, pdf.NodeInfo{
-			Name:         node.Name,
-			MemoryGB:     memGB,
-			HardwareCost: hardwareCost,
-			ElecCost:     elecCost,
-			TotalCost:    hardwareCost + elecCost,
-		})
-	}
-	return result
+// printFleet prints per-cluster totals and the namespace rollup across
+// clusters. Use --context to see one cluster in full.
+func printFleet(fleet *cost.Fleet) {
+	fmt.Printf("Found %d pods across %d nodes in %d clusters\n\n", fleet.Pods, fleet.Nodes, len(fleet.Clusters))
+
+	fmt.Printf("=== Fleet Cost Summary (monthly) ===\n")
+	fmt.Printf("%-30s %-10s %-6s %-6s %-12s %-12s %-12s\n", "CONTEXT", "DIST", "NODES", "PODS", "PODS $", "IDLE $", "TOTAL $")
+	for _, c := range fleet.Clusters {
+		r := c.Report
+		fmt.Printf("%-30s %-10s %-6d %-6d $%-11.2f $%-11.2f $%-11.2f\n",
+			truncate(c.Context, 30),
+			r.Distribution,
+			len(r.Nodes),
+			len(r.Pods),
+			r.PodTotalCost,
+			r.IdleCost,
+			r.TotalCost)
+	}
+	fmt.Printf("%-30s %-10s %-6d %-6d $%-11.2f $%-11.2f $%-11.2f\n",
+		"TOTAL", "", fleet.Nodes, fleet.Pods, fleet.PodTotalCost, fleet.IdleCost, fleet.TotalCost)
+
+	fmt.Printf("\n=== Namespace Costs by Cluster (monthly) ===\n")
+	fmt.Printf("%-30s %-40s %-6s %-12s\n", "CONTEXT", "NAMESPACE", "PODS", "MONTHLY $")
+	for _, ns := range fleet.Namespaces {
+		if ns.Cost > 0 {
+			fmt.Printf("%-30s %-40s %-6d $%-11.2f\n", truncate(ns.Context, 30), truncate(ns.Name, 40), ns.Pods, ns.Cost)
+		}
+	}
 }
 
-func calculateClusterCosts(nodes []corev1.Node) (float64, float64, float64) {
-	var hardwareCost float64
-	for _, node := range nodes {
-		nodeHardware, _, _ := calculateNodeHardwareCost(node)
-		hardwareCost += nodeHardware
+// orDash returns s, or "-" for an empty table cell.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
 	}
+	return s
+}
 
-	elecCost := float64(len(nodes)) * cfg.Pricing.WattsPerNode / 1000.0 * 730 * cfg.Pricing.ElectricityRate
-	controlPlaneCost := 0.0
-	if isEKSCluster(nodes) {
-		controlPlaneCost = 730 * cfg.Pricing.EKS.ControlPlanePerHour
+// extendedHeader returns one column header per billed extended resource.
+func extendedHeader(names []corev1.ResourceName) string {
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%-16s ", truncate(string(name), 16))
 	}
-
-	return hardwareCost, elecCost, controlPlaneCost
+	return b.String()
 }
 
-func isEKSCluster(nodes []corev1.Node) bool {
-	for _, node := range nodes {
-		for k := range node.Labels {
-			if strings.HasPrefix(k, "eks.amazonaws.com/") {
-				return true
-			}
-		}
-		if strings.HasPrefix(node.Spec.ProviderID, "aws://") {
-			return true
+// extendedCells returns rl's quantity for each of names, aligned with
+// extendedHeader.
+func extendedCells(names []corev1.ResourceName, rl corev1.ResourceList) string {
+	var b strings.Builder
+	for _, name := range names {
+		cell := "-"
+		if q, ok := rl[name]; ok && !q.IsZero() {
+			cell = q.String()
 		}
+		fmt.Fprintf(&b, "%-16s ", cell)
 	}
-	return false
+	return b.String()
 }
 
-func resolveUsageRates() {
-	base := pricing.NewStaticProvider(cfg.Pricing.Cloud.CPUPerHour, cfg.Pricing.Cloud.MemPerGBHour)
-	activeUsageRatesSource = base.Source()
-
-	cmd := strings.TrimSpace(cfg.Pricing.MCP.Command)
-	if cmd == "" {
-		rates, _ := base.UsageRates(context.Background())
-		activeUsageRates = rates
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	rates, err := pricing.NewMCPProvider(cmd, cfg.Pricing.MCP.Args).UsageRates(ctx)
-	if err != nil {
-		log.Printf("warning: mcp pricing failed, falling back to config rates: %v", err)
-		rates, _ = base.UsageRates(context.Background())
-		activeUsageRatesSource = base.Source()
-		activeUsageRates = rates
-		return
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
 	}
-	activeUsageRatesSource = "mcp"
-	activeUsageRates = rates
+	return s[:maxLen]
 }
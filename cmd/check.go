@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/newman-bot/kfin/pkg/cost"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of kfin check. A context that could not be checked is an
+// error, so the check fails closed.
+const (
+	checkErrorExitCode  = 1
+	checkBreachExitCode = 2
+)
+
+// checkRow is one evaluated budget in the json output.
+type checkRow struct {
+	Context string  `json:"context"`
+	Scope   string  `json:"scope"`
+	Name    string  `json:"name"`
+	Source  string  `json:"source"`
+	Limit   float64 `json:"limit"`
+	Cost    float64 `json:"cost"`
+	Burn    float64 `json:"burn"`
+	Status  string  `json:"status"`
+}
+
+func CheckCmd() *cobra.Command {
+	output := "table"
+	failOn := "critical"
+	opts := defaultReportOptions()
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check monthly cost against budgets and exit non-zero on breach",
+		Long: `Evaluate the current monthly cost against the cluster, namespace and label
+budgets in the budgets section of the config and the kfin.io/budget namespace
+annotations. Prints every budget with its burn and exits 2 when one reaches
+the --fail-on status, so it can gate a CI pipeline. Errors exit 1, including
+any selected context that could not be reached.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			os.Exit(runCheck(output, failOn, opts))
+		},
+	}
+	checkCmd.Flags().StringVarP(&output, "output", "o", output, "Output format: table or json")
+	checkCmd.Flags().StringVar(&failOn, "fail-on", failOn, "Lowest budget status that fails the check: warn or critical")
+	addReportFlags(checkCmd, &opts)
+	return checkCmd
+}
+
+func runCheck(output, failOn string, opts reportOptions) int {
+	if output != "table" && output != "json" {
+		log.Fatalf("Invalid --output %q (expected: table or json)", output)
+	}
+	floor, err := parseBudgetStatus(failOn)
+	if err != nil {
+		log.Fatalf("Invalid --fail-on: %v", err)
+	}
+	reports, skipped, err := buildClusterReportsSkipping(context.Background(), opts)
+	if err != nil {
+		log.Fatalf("Failed to build cost report: %v", err)
+	}
+
+	rows := checkRows(reports)
+	violations := countViolations(reports, floor)
+	if output == "json" {
+		err = writeCheckJSON(os.Stdout, rows)
+	} else {
+		err = writeCheckTable(os.Stdout, rows, violations, floor)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write budget check: %v", err)
+	}
+	for _, s := range skipped {
+		log.Printf("error: context %s not checked: %v", s.context, s.err)
+	}
+	return checkExitCode(violations, skipped)
+}
+
+// checkExitCode fails closed: an unchecked context is an error even when
+// every checked budget passed.
+func checkExitCode(violations int, skipped []skippedContext) int {
+	switch {
+	case len(skipped) > 0:
+		return checkErrorExitCode
+	case violations > 0:
+		return checkBreachExitCode
+	default:
+		return 0
+	}
+}
+
+func parseBudgetStatus(s string) (cost.BudgetStatus, error) {
+	for _, status := range []cost.BudgetStatus{cost.BudgetWarn, cost.BudgetCritical} {
+		if strings.EqualFold(strings.TrimSpace(s), status.String()) {
+			return status, nil
+		}
+	}
+	return 0, fmt.Errorf("%q (expected: warn or critical)", s)
+}
+
+// countViolations counts the budgets in the reports at or past floor.
+func countViolations(reports []cost.ClusterReport, floor cost.BudgetStatus) int {
+	n := 0
+	for _, r := range reports {
+		for _, b := range r.Report.Budgets {
+			if b.Status >= floor {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// checkRows flattens the reports' budgets, keeping each report's order.
+func checkRows(reports []cost.ClusterReport) []checkRow {
+	rows := []checkRow{}
+	for _, r := range reports {
+		for _, b := range r.Report.Budgets {
+			rows = append(rows, checkRow{
+				Context: r.Context,
+				Scope:   b.Scope,
+				Name:    b.Name,
+				Source:  b.Source,
+				Limit:   b.Limit,
+				Cost:    b.Cost,
+				Burn:    b.Burn,
+				Status:  b.Status.String(),
+			})
+		}
+	}
+	return rows
+}
+
+func writeCheckTable(w io.Writer, rows []checkRow, violations int, floor cost.BudgetStatus) error {
+	if len(rows) == 0 {
+		_, err := fmt.Fprintln(w, "No budgets configured.")
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "%-8s %-20s %-10s %-32s %-12s %-12s %-7s %s\n", "STATUS", "CONTEXT", "SCOPE", "NAME", "BUDGET $/MO", "COST $/MO", "BURN", "SOURCE"); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if _, err := fmt.Fprintf(w, "%-8s %-20s %-10s %-32s $%-11.2f $%-11.2f %-7s %s\n",
+			strings.ToUpper(r.Status),
+			truncate(orDash(r.Context), 20),
+			r.Scope,
+			truncate(r.Name, 32),
+			r.Limit,
+			r.Cost,
+			fmt.Sprintf("%.0f%%", r.Burn*100),
+			r.Source); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d budgets, %d at or over %s\n", len(rows), violations, floor)
+	return err
+}
+
+func writeCheckJSON(w io.Writer, rows []checkRow) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
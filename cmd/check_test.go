@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/cost"
+)
+
+func TestCheck_FailOn(t *testing.T) {
+	report := &cost.Report{Budgets: []cost.BudgetResult{
+		{Scope: cost.BudgetNamespace, Name: "shop", Source: cost.BudgetSourceConfig, Limit: 100, Cost: 130, Burn: 1.3, Status: cost.BudgetCritical},
+		{Scope: cost.BudgetLabel, Name: "team=search", Source: cost.BudgetSourceConfig, Limit: 100, Cost: 85, Burn: 0.85, Status: cost.BudgetWarn},
+		{Scope: cost.BudgetCluster, Name: cost.BudgetCluster, Source: cost.BudgetSourceConfig, Limit: 1000, Cost: 215, Burn: 0.215, Status: cost.BudgetOK},
+	}}
+	reports := []cost.ClusterReport{{Context: "prod", Report: report}}
+
+	for failOn, want := range map[string]int{"critical": 1, "WARN": 2} {
+		floor, err := parseBudgetStatus(failOn)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := countViolations(reports, floor); got != want {
+			t.Fatalf("--fail-on %s: expected %d violations, got %d", failOn, want, got)
+		}
+	}
+	if got := checkExitCode(1, nil); got != checkBreachExitCode {
+		t.Fatalf("expected exit %d on breach, got %d", checkBreachExitCode, got)
+	}
+	if got := checkExitCode(0, nil); got != 0 {
+		t.Fatalf("expected exit 0 without violations, got %d", got)
+	}
+	// A context that failed must fail the check even if the rest pass.
+	skipped := []skippedContext{{context: "prod-euw1", err: errors.New("forbidden")}}
+	if got := checkExitCode(0, skipped); got != checkErrorExitCode {
+		t.Fatalf("expected exit %d for a skipped context, got %d", checkErrorExitCode, got)
+	}
+	if _, err := parseBudgetStatus("ok"); err == nil {
+		t.Fatalf("expected error for --fail-on ok")
+	}
+
+	var buf bytes.Buffer
+	if err := writeCheckTable(&buf, checkRows(reports), 1, cost.BudgetCritical); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "CRITICAL") || !strings.Contains(out, "130%") || !strings.Contains(out, "3 budgets, 1 at or over critical") {
+		t.Fatalf("unexpected table:\n%s", out)
+	}
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/newman-bot/kfin/pkg/cost"
+	"github.com/spf13/cobra"
+)
+
+// findingRow is one finding in the json output.
+type findingRow struct {
+	Context       string  `json:"context"`
+	Rule          string  `json:"rule"`
+	Severity      string  `json:"severity"`
+	Namespace     string  `json:"namespace,omitempty"`
+	Object        string  `json:"object"`
+	Message       string  `json:"message"`
+	MonthlyImpact float64 `json:"monthly_impact"`
+}
+
+func FindingsCmd() *cobra.Command {
+	output := "table"
+	minSeverity := "low"
+	opts := defaultReportOptions()
+	findingsCmd := &cobra.Command{
+		Use:   "findings",
+		Short: "Report waste and hygiene problems with severity and monthly impact",
+		Long: `Flag containers without requests or with limits but no requests, containers
+requesting far more than they use, pods stuck Pending, nodes with low request
+utilization, PVCs no pod mounts and namespaces without running pods. Each
+finding has a severity and an estimated monthly dollar impact.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			runFindings(output, minSeverity, opts)
+		},
+	}
+	findingsCmd.Flags().StringVarP(&output, "output", "o", output, "Output format: table or json")
+	findingsCmd.Flags().StringVar(&minSeverity, "severity", minSeverity, "Lowest severity to show: low, medium or high")
+	addReportFlags(findingsCmd, &opts)
+	return findingsCmd
+}
+
+func runFindings(output, minSeverity string, opts reportOptions) {
+	if output != "table" && output != "json" {
+		log.Fatalf("Invalid --output %q (expected: table or json)", output)
+	}
+	floor, err := parseSeverity(minSeverity)
+	if err != nil {
+		log.Fatalf("Invalid --severity: %v", err)
+	}
+	opts.containerUsage = true
+	reports, err := buildClusterReports(context.Background(), opts)
+	if err != nil {
+		log.Fatalf("Failed to build cost report: %v", err)
+	}
+
+	rows := findingRows(reports, floor)
+	if output == "json" {
+		err = writeFindingsJSON(os.Stdout, rows)
+	} else {
+		err = writeFindingsTable(os.Stdout, rows)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write findings: %v", err)
+	}
+}
+
+func parseSeverity(s string) (cost.Severity, error) {
+	for _, sev := range []cost.Severity{cost.SeverityLow, cost.SeverityMedium, cost.SeverityHigh} {
+		if strings.EqualFold(strings.TrimSpace(s), sev.String()) {
+			return sev, nil
+		}
+	}
+	return 0, fmt.Errorf("%q (expected: low, medium or high)", s)
+}
+
+// findingRows flattens the reports' findings at or above floor, keeping each
+// report's order.
+func findingRows(reports []cost.ClusterReport, floor cost.Severity) []findingRow {
+	rows := []findingRow{}
+	for _, r := range reports {
+		for _, f := range r.Report.Findings {
+			if f.Severity < floor {
+				continue
+			}
+			rows = append(rows, findingRow{
+				Context:       r.Context,
+				Rule:          f.Rule,
+				Severity:      f.Severity.String(),
+				Namespace:     f.Namespace,
+				Object:        f.Object,
+				Message:       f.Message,
+				MonthlyImpact: f.MonthlyImpact,
+			})
+		}
+	}
+	return rows
+}
+
+func writeFindingsTable(w io.Writer, rows []findingRow) error {
+	if len(rows) == 0 {
+		_, err := fmt.Fprintln(w, "No findings.")
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "%-8s %-24s %-20s %-44s %-12s %s\n", "SEVERITY", "RULE", "NAMESPACE", "OBJECT", "IMPACT $/MO", "DETAIL"); err != nil {
+		return err
+	}
+	var total float64
+	for _, r := range rows {
+		total += r.MonthlyImpact
+		if _, err := fmt.Fprintf(w, "%-8s %-24s %-20s %-44s $%-11.2f %s\n",
+			strings.ToUpper(r.Severity),
+			r.Rule,
+			truncate(orDash(r.Namespace), 20),
+			truncate(r.Object, 44),
+			r.MonthlyImpact,
+			r.Message); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d findings, $%.2f/month at stake\n", len(rows), total)
+	return err
+}
+
+func writeFindingsJSON(w io.Writer, rows []findingRow) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
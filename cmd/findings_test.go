@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/cost"
+)
+
+func TestFindingRows_SeverityFloor(t *testing.T) {
+	report := &cost.Report{Findings: []cost.Finding{
+		{Rule: cost.RulePendingPod, Severity: cost.SeverityHigh, Object: "pod/stuck", MonthlyImpact: 120},
+		{Rule: cost.RuleEmptyNamespace, Severity: cost.SeverityLow, Object: "namespace/old"},
+	}}
+	reports := []cost.ClusterReport{{Context: "prod", Report: report}}
+
+	floor, err := parseSeverity("Medium")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows := findingRows(reports, floor)
+	if len(rows) != 1 || rows[0].Object != "pod/stuck" || rows[0].Severity != "high" || rows[0].Context != "prod" {
+		t.Fatalf("expected only the high finding, got %+v", rows)
+	}
+	if _, err := parseSeverity("urgent"); err == nil {
+		t.Fatalf("expected error for unknown severity")
+	}
+}
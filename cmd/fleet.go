@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/newman-bot/kfin/pkg/cost"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// clusterTarget is one kubeconfig context to build a report for.
+type clusterTarget struct {
+	context   string
+	cluster   string
+	statsURL  string
+	clientset kubernetes.Interface
+}
+
+// kubeConfigFor loads kubeconfig the way kubectl does, switched to
+// contextName; an empty name keeps the current context.
+func kubeConfigFor(contextName string) clientcmd.ClientConfig {
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	)
+}
+
+// targetContexts returns the context names selected by --context and
+// --all-contexts, sorted and deduplicated, or the current context when
+// neither is set.
+func targetContexts(raw clientcmdapi.Config, opts reportOptions) ([]string, error) {
+	names := opts.contexts
+	if opts.allContexts {
+		names = make([]string, 0, len(raw.Contexts))
+		for name := range raw.Contexts {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		// Empty when running in-cluster without a kubeconfig.
+		return []string{raw.CurrentContext}, nil
+	}
+
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := raw.Contexts[name]; !ok {
+			return nil, fmt.Errorf("context %q not found in kubeconfig", name)
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// resolveTargets builds a client for every selected context.
+func resolveTargets(opts reportOptions) ([]clusterTarget, error) {
+	raw, err := kubeConfigFor("").RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load kubeconfig: %w", err)
+	}
+	names, err := targetContexts(raw, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]clusterTarget, 0, len(names))
+	for _, name := range names {
+		restCfg, err := kubeConfigFor(name).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("context %s: %w", name, err)
+		}
+		clientset, err := kubernetes.NewForConfig(restCfg)
+		if err != nil {
+			return nil, fmt.Errorf("context %s: %w", name, err)
+		}
+		contextName, cluster := name, "unknown"
+		if contextName == "" {
+			contextName = "unknown"
+		}
+		if kctx, ok := raw.Contexts[name]; ok && kctx != nil && kctx.Cluster != "" {
+			cluster = kctx.Cluster
+		}
+		targets = append(targets, clusterTarget{
+			context:   contextName,
+			cluster:   cluster,
+			statsURL:  statsBaseURL(name),
+			clientset: clientset,
+		})
+	}
+	return targets, nil
+}
+
+// statsBaseURL returns the Prometheus endpoint for a context:
+// stats.base_url_by_context when set, else stats.base_url.
+func statsBaseURL(contextName string) string {
+	if url := strings.TrimSpace(cfg.Stats.BaseURLByContext[contextName]); url != "" {
+		return url
+	}
+	return strings.TrimSpace(cfg.Stats.BaseURL)
+}
+
+// skippedContext is a selected context no report could be built for.
+type skippedContext struct {
+	context string
+	err     error
+}
+
+// buildClusterReports builds a report for every selected context
+// concurrently, in context order. With several contexts, a cluster that
+// fails is logged and left out; the call only fails when every cluster does.
+func buildClusterReports(ctx context.Context, opts reportOptions) ([]cost.ClusterReport, error) {
+	reports, skipped, err := buildClusterReportsSkipping(ctx, opts)
+	for _, s := range skipped {
+		log.Printf("warning: context %s skipped: %v", s.context, s.err)
+	}
+	return reports, err
+}
+
+// buildClusterReportsSkipping is buildClusterReports, returning the skipped
+// contexts instead of logging them, for callers that must not ignore them.
+func buildClusterReportsSkipping(ctx context.Context, opts reportOptions) ([]cost.ClusterReport, []skippedContext, error) {
+	targets, err := resolveTargets(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	calc, err := newCalculator(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reports := make([]*cost.Report, len(targets))
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target clusterTarget) {
+			defer wg.Done()
+			reports[i], errs[i] = buildReport(ctx, calc, target, opts)
+		}(i, target)
+	}
+	wg.Wait()
+
+	if len(targets) == 1 && errs[0] != nil {
+		return nil, nil, errs[0]
+	}
+
+	var out []cost.ClusterReport
+	var skipped []skippedContext
+	for i, target := range targets {
+		if errs[i] != nil {
+			skipped = append(skipped, skippedContext{context: target.context, err: errs[i]})
+			continue
+		}
+		out = append(out, cost.ClusterReport{Context: target.context, Cluster: target.cluster, Report: reports[i]})
+	}
+	if len(out) == 0 {
+		return nil, skipped, fmt.Errorf("no cluster could be reported on")
+	}
+	return out, skipped, nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestTargetContexts(t *testing.T) {
+	raw := clientcmdapi.Config{
+		CurrentContext: "dev",
+		Contexts: map[string]*clientcmdapi.Context{
+			"dev":     {Cluster: "dev"},
+			"prod":    {Cluster: "prod"},
+			"staging": {Cluster: "staging"},
+		},
+	}
+	cases := []struct {
+		name string
+		opts reportOptions
+		want []string
+	}{
+		{"current context", reportOptions{}, []string{"dev"}},
+		{"explicit contexts", reportOptions{contexts: []string{"staging", "prod", "staging"}}, []string{"prod", "staging"}},
+		{"all contexts", reportOptions{allContexts: true}, []string{"dev", "prod", "staging"}},
+	}
+	for _, tc := range cases {
+		got, err := targetContexts(raw, tc.opts)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	if _, err := targetContexts(raw, reportOptions{contexts: []string{"missing"}}); err == nil {
+		t.Fatalf("expected error for unknown context")
+	}
+}
+
+func TestStatsBaseURL_PerContext(t *testing.T) {
+	prev := cfg
+	cfg = config.DefaultConfig()
+	cfg.Stats.BaseURL = "http://default"
+	cfg.Stats.BaseURLByContext = map[string]string{"prod": "http://prod"}
+	t.Cleanup(func() { cfg = prev })
+
+	if got := statsBaseURL("prod"); got != "http://prod" {
+		t.Fatalf("expected prod override, got %s", got)
+	}
+	if got := statsBaseURL("dev"); got != "http://default" {
+		t.Fatalf("expected default base url, got %s", got)
+	}
+}
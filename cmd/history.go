@@ -3,12 +3,17 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"log"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/newman-bot/kfin/pkg/cost"
 	"github.com/newman-bot/kfin/pkg/pricing"
 	"github.com/newman-bot/kfin/pkg/stats"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -16,6 +21,13 @@ const (
 	defaultMemQuery = `sum(container_memory_working_set_bytes{container!="",pod!=""})`
 )
 
+// Forecasts default to four weeks of hourly samples, enough for weekly
+// seasonality.
+const (
+	forecastLookbackHours = 28 * 24
+	forecastStep          = time.Hour
+)
+
 func HistoryCmd() *cobra.Command {
 	lookbackHours := cfg.Stats.DefaultLookbackHours
 	step := "5m"
@@ -23,12 +35,25 @@ func HistoryCmd() *cobra.Command {
 	pricingSource := "config"
 	mcpCommand := strings.TrimSpace(cfg.Pricing.MCP.Command)
 	mcpArgs := append([]string{}, cfg.Pricing.MCP.Args...)
+	by := ""
+	top := 20
+	network := cfg.Pricing.Network.Enabled
+	forecast := false
+	weekly := true
 
 	cmd := &cobra.Command{
 		Use:   "history",
 		Short: "Analyze historical cluster usage from Prometheus-compatible stats API",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runHistory(lookbackHours, step, debug, pricingSource, mcpCommand, mcpArgs)
+			if forecast {
+				if !cmd.Flags().Changed("hours") {
+					lookbackHours = forecastLookbackHours
+				}
+				if !cmd.Flags().Changed("step") {
+					step = forecastStep.String()
+				}
+			}
+			return runHistory(lookbackHours, step, debug, pricingSource, mcpCommand, mcpArgs, by, top, network, forecast, weekly)
 		},
 	}
 
@@ -38,11 +63,16 @@ func HistoryCmd() *cobra.Command {
 	cmd.Flags().StringVar(&pricingSource, "pricing-source", pricingSource, "Pricing source: config or mcp")
 	cmd.Flags().StringVar(&mcpCommand, "pricing-mcp-command", mcpCommand, "Command used to fetch pricing JSON from MCP wrapper")
 	cmd.Flags().StringArrayVar(&mcpArgs, "pricing-mcp-arg", mcpArgs, "Repeatable arg passed to --pricing-mcp-command")
+	cmd.Flags().StringVar(&by, "by", by, "Break usage down by namespace, pod or container")
+	cmd.Flags().IntVar(&top, "top", top, "Number of rows shown with --by")
+	cmd.Flags().BoolVar(&network, "network", network, "Show projected network egress and cross-zone cost per namespace")
+	cmd.Flags().BoolVar(&forecast, "forecast", forecast, "Project end-of-month and next-month cost from daily usage (default window 28 days, step 1h)")
+	cmd.Flags().BoolVar(&weekly, "weekly", weekly, "Fit weekly seasonality in --forecast when the window covers two weeks")
 
 	return cmd
 }
 
-func runHistory(lookbackHours int, step string, debug bool, pricingSource, mcpCommand string, mcpArgs []string) error {
+func runHistory(lookbackHours int, step string, debug bool, pricingSource, mcpCommand string, mcpArgs []string, by string, top int, network, forecast, weekly bool) error {
 	baseURL := strings.TrimSpace(cfg.Stats.BaseURL)
 	if baseURL == "" {
 		return fmt.Errorf("stats.base_url is empty; set it in config.yaml (example: http://stats.kramerica.ai)")
@@ -58,6 +88,13 @@ func runHistory(lookbackHours int, step string, debug bool, pricingSource, mcpCo
 	if stepDur <= 0 {
 		return fmt.Errorf("--step must be greater than 0")
 	}
+	var level stats.UsageLevel
+	if by != "" {
+		level, err = stats.ParseUsageLevel(by)
+		if err != nil {
+			return fmt.Errorf("invalid --by: %w", err)
+		}
+	}
 
 	timeout := time.Duration(cfg.Stats.QueryTimeoutSeconds) * time.Second
 	client, err := stats.NewClient(baseURL, timeout)
@@ -71,8 +108,10 @@ func runHistory(lookbackHours int, step string, debug bool, pricingSource, mcpCo
 
 	end := time.Now()
 	start := end.Add(-time.Duration(lookbackHours) * time.Hour)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	// Each query gets its own timeout so a slow one does not starve the rest.
+	queryCtx := func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), timeout)
+	}
 
 	cpuURL, err := client.QueryRangeURL(defaultCPUQuery, start, end, stepDur)
 	if err != nil {
@@ -90,11 +129,15 @@ func runHistory(lookbackHours int, step string, debug bool, pricingSource, mcpCo
 		fmt.Printf("Memory query URL: %s\n\n", memURL)
 	}
 
+	ctx, cancel := queryCtx()
 	cpuResp, err := client.QueryRange(ctx, defaultCPUQuery, start, end, stepDur)
+	cancel()
 	if err != nil {
 		return fmt.Errorf("query cpu usage: %w", err)
 	}
+	ctx, cancel = queryCtx()
 	memResp, err := client.QueryRange(ctx, defaultMemQuery, start, end, stepDur)
+	cancel()
 	if err != nil {
 		return fmt.Errorf("query memory usage: %w", err)
 	}
@@ -108,13 +151,15 @@ func runHistory(lookbackHours int, step string, debug bool, pricingSource, mcpCo
 		return fmt.Errorf("parse memory usage response: %w", err)
 	}
 
-	avgMemGB := avgMemBytes / (1024 * 1024 * 1024)
+	avgMemGB := avgMemBytes / cost.BytesPerGB
+	ctx, cancel = queryCtx()
 	usageRates, err := pricingProvider.UsageRates(ctx)
+	cancel()
 	if err != nil {
 		return fmt.Errorf("resolve pricing rates: %w", err)
 	}
-	monthlyCPUCost := avgCPU * 730 * usageRates.CPUPerHour
-	monthlyMemCost := avgMemGB * 730 * usageRates.MemPerGBHour
+	monthlyCPUCost := avgCPU * cost.HoursPerMonth * usageRates.CPUPerHour
+	monthlyMemCost := avgMemGB * cost.HoursPerMonth * usageRates.MemPerGBHour
 	cpuPointStats := stats.GetSeriesPointStats(cpuResp)
 	memPointStats := stats.GetSeriesPointStats(memResp)
 
@@ -142,9 +187,155 @@ func runHistory(lookbackHours int, step string, debug bool, pricingSource, mcpCo
 	fmt.Printf("Memory:           $%.2f\n", monthlyMemCost)
 	fmt.Printf("Total:            $%.2f\n", monthlyCPUCost+monthlyMemCost)
 
+	if forecast {
+		f, err := forecastUsageCost(cpuResp, memResp, usageRates, end, weekly)
+		if err != nil {
+			return fmt.Errorf("forecast: %w", err)
+		}
+		printForecast(f, debug)
+	}
+
+	if by != "" {
+		ctx, cancel := queryCtx()
+		usage, err := client.Usage(ctx, level, start, end, stepDur)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("query usage by %s: %w", by, err)
+		}
+		printUsageBreakdown(usage, usageRates, by, top)
+	}
+
+	if network {
+		ctx, cancel := queryCtx()
+		tx, err := client.PodNetworkTransmit(ctx, start, end, stepDur)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("query network usage: %w", err)
+		}
+		pods, nodes := listPodsAndNodes(timeout)
+		_, byNS := cost.NetworkCosts(cfg.Pricing.Network, nodes, cost.PodNodes(pods), tx)
+		printNetworkBreakdown(byNS, top)
+	}
+
 	return nil
 }
 
+// forecastUsageCost buckets the CPU and memory range queries into daily
+// usage cost and fits a forecast to it.
+func forecastUsageCost(cpuResp, memResp *stats.QueryRangeResponse, rates pricing.UsageRates, now time.Time, weekly bool) (*cost.Forecast, error) {
+	days := cost.DailyUsageCosts(stats.DailyAverages(cpuResp), stats.DailyAverages(memResp), rates)
+	return cost.ForecastCosts(days, now, weekly)
+}
+
+func printForecast(f *cost.Forecast, debug bool) {
+	model := "linear trend"
+	if f.Weekly {
+		model += " + weekly seasonality"
+	}
+	fmt.Printf("\nForecast (usage-based, %s, %d%% band)\n", model, cost.ForecastBandPercent)
+	latest := f.Days[len(f.Days)-1]
+	fmt.Printf("History:          %d days, latest $%.2f/day, trend %+.2f $/day per day\n", len(f.Days), latest.Cost, f.Slope)
+	printProjection("End of "+f.EndOfMonth.Month.Format("Jan"), f.EndOfMonth)
+	printProjection("Next month ("+f.NextMonth.Month.Format("Jan")+")", f.NextMonth)
+	if debug {
+		fmt.Printf("\n%-12s %10s\n", "DAY (UTC)", "COST $")
+		for _, d := range f.Days {
+			fmt.Printf("%-12s %10.2f\n", d.Day.Format("2006-01-02"), d.Cost)
+		}
+	}
+}
+
+func printProjection(label string, p cost.Projection) {
+	fmt.Printf("%-17s $%.2f  ($%.2f - $%.2f", label+":", p.Cost, p.Low, p.High)
+	if p.Observed > 0 {
+		fmt.Printf(", $%.2f observed", p.Observed)
+	}
+	fmt.Printf(")\n")
+}
+
+// listPodsAndNodes lists pods and nodes for zone-aware network pricing. It is
+// best effort: without cluster access all in-cluster traffic is priced as
+// in-zone. Each listing is bounded by timeout.
+func listPodsAndNodes(timeout time.Duration) ([]corev1.Pod, []corev1.Node) {
+	clientset, err := getClientset()
+	if err != nil {
+		log.Printf("warning: no cluster access, cross-zone traffic not estimated: %v", err)
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	cancel()
+	if err != nil {
+		log.Printf("warning: list pods failed, cross-zone traffic not estimated: %v", err)
+		return nil, nil
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	cancel()
+	if err != nil {
+		log.Printf("warning: list nodes failed, cross-zone traffic not estimated: %v", err)
+		return nil, nil
+	}
+	return pods.Items, nodes.Items
+}
+
+func printNetworkBreakdown(costs []cost.NetworkCost, top int) {
+	var total float64
+	for _, nc := range costs {
+		total += nc.Cost
+	}
+	if top > 0 && len(costs) > top {
+		costs = costs[:top]
+	}
+
+	fmt.Printf("\nNetwork by namespace (projected monthly GB)\n")
+	fmt.Printf("%-40s %6s %12s %12s %12s %10s\n", "NAMESPACE", "PODS", "INTERNET", "CROSS-ZONE", "IN-ZONE", "MONTHLY $")
+	for _, nc := range costs {
+		fmt.Printf("%-40s %6d %12.1f %12.1f %12.1f %10.2f\n",
+			truncate(nc.Namespace, 40), nc.Pods,
+			nc.Traffic.InternetGB, nc.Traffic.CrossZoneGB, nc.Traffic.InZoneGB,
+			nc.Cost)
+	}
+	fmt.Printf("%-40s %6s %12s %12s %12s %10.2f\n", "TOTAL", "", "", "", "", total)
+}
+
+type usageRow struct {
+	key   stats.UsageKey
+	usage stats.Usage
+	cost  float64
+}
+
+func printUsageBreakdown(usage map[stats.UsageKey]stats.Usage, rates pricing.UsageRates, by string, top int) {
+	rows := make([]usageRow, 0, len(usage))
+	for key, u := range usage {
+		memGB := u.Memory.Avg / cost.BytesPerGB
+		rows = append(rows, usageRow{
+			key:   key,
+			usage: u,
+			cost:  (u.CPU.Avg*rates.CPUPerHour + memGB*rates.MemPerGBHour) * cost.HoursPerMonth,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].cost == rows[j].cost {
+			return rows[i].key.String() < rows[j].key.String()
+		}
+		return rows[i].cost > rows[j].cost
+	})
+	if top > 0 && len(rows) > top {
+		rows = rows[:top]
+	}
+
+	fmt.Printf("\nUsage by %s (cores / GB)\n", by)
+	fmt.Printf("%-48s %8s %8s %8s %8s %8s %10s\n", strings.ToUpper(by), "CPU AVG", "CPU P95", "CPU MAX", "MEM AVG", "MEM MAX", "MONTHLY $")
+	for _, r := range rows {
+		fmt.Printf("%-48s %8.3f %8.3f %8.3f %8.2f %8.2f %10.2f\n",
+			truncate(r.key.String(), 48),
+			r.usage.CPU.Avg, r.usage.CPU.P95, r.usage.CPU.Max,
+			r.usage.Memory.Avg/cost.BytesPerGB, r.usage.Memory.Max/cost.BytesPerGB,
+			r.cost)
+	}
+}
+
 func buildPricingProvider(source, mcpCommand string, mcpArgs []string) (pricing.Provider, error) {
 	switch strings.ToLower(strings.TrimSpace(source)) {
 	case "", "config":
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/newman-bot/kfin/pkg/pricing"
+	"github.com/newman-bot/kfin/pkg/stats"
+)
+
+// hourlyResponse is a single-series range response with one value per hour
+// from start, value(day) for each hour of that day.
+func hourlyResponse(start time.Time, days int, value func(day int) float64) *stats.QueryRangeResponse {
+	resp := &stats.QueryRangeResponse{Status: "success"}
+	var series stats.MatrixSeries
+	for h := 0; h < days*24; h++ {
+		ts := float64(start.Add(time.Duration(h) * time.Hour).Unix())
+		series.Values = append(series.Values, []interface{}{ts, strconv.FormatFloat(value(h/24), 'f', -1, 64)})
+	}
+	resp.Data.Result = []stats.MatrixSeries{series}
+	return resp
+}
+
+func TestForecastUsageCost(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	cpu := hourlyResponse(start, 10, func(day int) float64 { return 10 + float64(day) })
+	mem := hourlyResponse(start, 10, func(int) float64 { return 0 })
+	rates := pricing.UsageRates{CPUPerHour: 0.05}
+
+	f, err := forecastUsageCost(cpu, mem, rates, start.AddDate(0, 0, 9), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// One more core a day is $1.20 more a day at $0.05 per core-hour.
+	if len(f.Days) != 10 || math.Abs(f.Slope-1.2) > 1e-9 {
+		t.Fatalf("expected 10 days trending $1.20/day, got %d days at %.4f", len(f.Days), f.Slope)
+	}
+
+	if _, err := forecastUsageCost(cpu, &stats.QueryRangeResponse{}, rates, start, true); err == nil {
+		t.Fatalf("expected error without memory history")
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/newman-bot/kfin/pkg/cost"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// listInventory lists the cluster objects the cost calculator needs. Pods and
+// nodes are required; owner lookups are best effort so restricted RBAC still
+// produces a report.
+func listInventory(ctx context.Context, clientset kubernetes.Interface) (cost.Inventory, error) {
+	var inv cost.Inventory
+
+	// List all pods across all namespaces
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return inv, fmt.Errorf("list pods: %w", err)
+	}
+	inv.Pods = pods.Items
+
+	// Get nodes for hardware cost calculation
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return inv, fmt.Errorf("list nodes: %w", err)
+	}
+	inv.Nodes = nodes.Items
+
+	replicaSets, err := clientset.AppsV1().ReplicaSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("warning: list replicasets failed, deployment rollup will use pod-template-hash: %v", err)
+	} else {
+		inv.ReplicaSets = replicaSets.Items
+	}
+
+	jobs, err := clientset.BatchV1().Jobs("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("warning: list jobs failed, cronjob rollup disabled: %v", err)
+	} else {
+		inv.Jobs = jobs.Items
+	}
+
+	statefulSets, err := clientset.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("warning: list statefulsets failed, limits-without-requests findings skip their pods: %v", err)
+	} else {
+		inv.StatefulSets = statefulSets.Items
+	}
+
+	daemonSets, err := clientset.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("warning: list daemonsets failed, limits-without-requests findings skip their pods: %v", err)
+	} else {
+		inv.DaemonSets = daemonSets.Items
+	}
+
+	claims, err := clientset.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("warning: list persistentvolumeclaims failed, storage cost disabled: %v", err)
+	} else {
+		inv.PersistentVolumeClaims = claims.Items
+	}
+
+	volumes, err := clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("warning: list persistentvolumes failed, orphaned volumes will not be costed: %v", err)
+	} else {
+		inv.PersistentVolumes = volumes.Items
+	}
+
+	services, err := clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("warning: list services failed, load balancer cost disabled: %v", err)
+	} else {
+		inv.Services = services.Items
+	}
+
+	if cfg.Pricing.LoadBalancers.IncludeIngress {
+		ingresses, err := clientset.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			log.Printf("warning: list ingresses failed, alb cost disabled: %v", err)
+		} else {
+			inv.Ingresses = ingresses.Items
+		}
+	}
+
+	namespaces, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("warning: list namespaces failed, label grouping will use pod labels only and ownership is unknown: %v", err)
+	} else {
+		inv.Namespaces = namespaces.Items
+	}
+
+	return inv, nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/newman-bot/kfin/pkg/cost"
+	"github.com/spf13/cobra"
+)
+
+// namespaceRow is one namespace in the namespaces export.
+type namespaceRow struct {
+	Context    string  `json:"context"`
+	Namespace  string  `json:"namespace"`
+	Owner      string  `json:"owner"`
+	CostCenter string  `json:"cost_center"`
+	Budget     float64 `json:"budget"`
+	Pods       int     `json:"pods"`
+	Cost       float64 `json:"monthly_cost"`
+}
+
+func NamespacesCmd() *cobra.Command {
+	var output string
+	var unowned bool
+	opts := defaultReportOptions()
+	namespacesCmd := &cobra.Command{
+		Use:   "namespaces",
+		Short: "List namespace costs with owner, cost center and budget annotations",
+		Long: `List each namespace's monthly cost with the kfin.io/owner, kfin.io/cost-center
+and kfin.io/budget annotations. Use --unowned to list only namespaces without
+an owner, and -o json or -o csv for machine-readable output.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			runNamespaces(output, unowned, opts)
+		},
+	}
+	namespacesCmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, json or csv")
+	namespacesCmd.Flags().BoolVar(&unowned, "unowned", false, "Only list namespaces without a kfin.io/owner annotation")
+	addReportFlags(namespacesCmd, &opts)
+	return namespacesCmd
+}
+
+func runNamespaces(output string, unowned bool, opts reportOptions) {
+	write, ok := namespaceWriters[output]
+	if !ok {
+		log.Fatalf("Invalid --output %q (expected: table, json or csv)", output)
+	}
+	reports, err := buildClusterReports(context.Background(), opts)
+	if err != nil {
+		log.Fatalf("Failed to build cost report: %v", err)
+	}
+	if err := write(os.Stdout, namespaceRows(reports, unowned)); err != nil {
+		log.Fatalf("Failed to write namespaces: %v", err)
+	}
+}
+
+// namespaceRows flattens the reports' namespace ownership listings, which
+// include namespaces without pods, and keeps only unowned namespaces when
+// unowned is set.
+func namespaceRows(reports []cost.ClusterReport, unowned bool) []namespaceRow {
+	rows := []namespaceRow{}
+	for _, r := range reports {
+		namespaces := r.Report.NamespaceOwnership
+		if unowned {
+			namespaces = cost.UnownedNamespaces(r.Report)
+		}
+		for _, ns := range namespaces {
+			rows = append(rows, namespaceRow{
+				Context:    r.Context,
+				Namespace:  ns.Name,
+				Owner:      ns.Owner,
+				CostCenter: ns.CostCenter,
+				Budget:     ns.Budget,
+				Pods:       ns.Pods,
+				Cost:       ns.Cost,
+			})
+		}
+	}
+	return rows
+}
+
+var namespaceWriters = map[string]func(io.Writer, []namespaceRow) error{
+	"table": writeNamespacesTable,
+	"json":  writeNamespacesJSON,
+	"csv":   writeNamespacesCSV,
+}
+
+func writeNamespacesTable(w io.Writer, rows []namespaceRow) error {
+	if _, err := fmt.Fprintf(w, "%-24s %-32s %-20s %-16s %-10s %-6s %-12s\n",
+		"CONTEXT", "NAMESPACE", "OWNER", "COST CENTER", "BUDGET $", "PODS", "MONTHLY $"); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		budget := "-"
+		if r.Budget > 0 {
+			budget = fmt.Sprintf("%.2f", r.Budget)
+		}
+		if _, err := fmt.Fprintf(w, "%-24s %-32s %-20s %-16s %-10s %-6d $%-11.2f\n",
+			truncate(r.Context, 24),
+			truncate(r.Namespace, 32),
+			truncate(orDash(r.Owner), 20),
+			truncate(orDash(r.CostCenter), 16),
+			budget,
+			r.Pods,
+			r.Cost); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeNamespacesJSON(w io.Writer, rows []namespaceRow) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
+
+func writeNamespacesCSV(w io.Writer, rows []namespaceRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"context", "namespace", "owner", "cost_center", "budget", "pods", "monthly_cost"}); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := cw.Write([]string{
+			r.Context,
+			r.Namespace,
+			r.Owner,
+			r.CostCenter,
+			strconv.FormatFloat(r.Budget, 'f', 2, 64),
+			strconv.Itoa(r.Pods),
+			strconv.FormatFloat(r.Cost, 'f', 2, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/cost"
+)
+
+func TestNamespaceRows(t *testing.T) {
+	report := &cost.Report{NamespaceOwnership: []cost.NamespaceCost{
+		{Name: "shop", Pods: 2, Cost: 40, Owner: "payments-team", CostCenter: "CC-1042", Budget: 250},
+		{Name: "legacy-batch", Pods: 1, Cost: 12},
+		{Name: "sandbox"},
+	}}
+	reports := []cost.ClusterReport{{Context: "prod", Report: report}}
+
+	if rows := namespaceRows(reports, false); len(rows) != 3 {
+		t.Fatalf("expected every listed namespace, got %+v", rows)
+	}
+	rows := namespaceRows(reports, true)
+	if len(rows) != 2 || rows[0].Namespace != "legacy-batch" || rows[1].Namespace != "sandbox" || rows[0].Context != "prod" {
+		t.Fatalf("expected legacy-batch then sandbox, got %+v", rows)
+	}
+}
+
+func TestNamespaceWriters(t *testing.T) {
+	rows := []namespaceRow{{Context: "prod", Namespace: "shop", Owner: "payments-team", CostCenter: "CC-1042", Budget: 250, Pods: 2, Cost: 40}}
+
+	var buf bytes.Buffer
+	if err := writeNamespacesJSON(&buf, rows); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if decoded[0]["owner"] != "payments-team" || decoded[0]["cost_center"] != "CC-1042" {
+		t.Fatalf("unexpected json row: %v", decoded[0])
+	}
+
+	buf.Reset()
+	if err := writeNamespacesCSV(&buf, rows); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "context,namespace,owner,cost_center,budget,pods,monthly_cost\nprod,shop,payments-team,CC-1042,250.00,2,40.00\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected csv:\n%s", got)
+	}
+}
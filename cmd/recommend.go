@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/newman-bot/kfin/pkg/cost"
+	"github.com/newman-bot/kfin/pkg/stats"
+	"github.com/spf13/cobra"
+)
+
+// recommendPatch is one entry of the machine-readable recommendation list.
+// Patch is a strategic merge patch for kubectl patch; it is omitted for
+// bare pods and Jobs, whose pod templates cannot be changed in place.
+// AtLimit lists the resources whose request was capped at the limit.
+type recommendPatch struct {
+	Kind           string            `json:"kind"`
+	Namespace      string            `json:"namespace"`
+	Name           string            `json:"name"`
+	Container      string            `json:"container"`
+	ContainerKind  string            `json:"container_kind"`
+	Replicas       int               `json:"replicas"`
+	Current        map[string]string `json:"current"`
+	Requests       map[string]string `json:"requests"`
+	AtLimit        []string          `json:"at_limit,omitempty"`
+	MonthlySavings float64           `json:"monthly_savings"`
+	Patch          interface{}       `json:"patch,omitempty"`
+}
+
+func RecommendCmd() *cobra.Command {
+	contextName := ""
+	namespace := ""
+	output := "table"
+	lookbackHours := cfg.Recommend.LookbackHours
+	headroom := cfg.Recommend.Headroom
+
+	cmd := &cobra.Command{
+		Use:   "recommend",
+		Short: "Suggest container requests from Prometheus usage percentiles",
+		Long: `Compare each container's CPU and memory requests with its p95 CPU and max
+memory usage over the lookback window, suggest requests with headroom capped
+at the container's limits, and estimate the monthly savings at the active
+usage rates. Native sidecars (restartable init containers) are included. -o json prints a
+patch list with a strategic merge patch per workload container.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRecommend(contextName, namespace, output, lookbackHours, headroom)
+		},
+	}
+	cmd.Flags().StringVar(&contextName, "context", contextName, "Kubeconfig context (default: current context)")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", namespace, "Only recommend for this namespace")
+	cmd.Flags().StringVarP(&output, "output", "o", output, "Output format: table or json")
+	cmd.Flags().IntVar(&lookbackHours, "hours", lookbackHours, "Usage lookback window in hours")
+	cmd.Flags().Float64Var(&headroom, "headroom", headroom, "Fraction added on top of observed usage, e.g. 0.2 for 20%")
+	return cmd
+}
+
+func runRecommend(contextName, namespace, output string, lookbackHours int, headroom float64) error {
+	if output != "table" && output != "json" {
+		return fmt.Errorf("invalid --output %q (expected: table or json)", output)
+	}
+	if lookbackHours <= 0 {
+		return fmt.Errorf("--hours must be greater than 0")
+	}
+	recCfg := cfg.Recommend
+	recCfg.Headroom = headroom
+	opts, err := cost.ParseRightsizeOptions(recCfg)
+	if err != nil {
+		return err
+	}
+
+	reportOpts := defaultReportOptions()
+	if contextName != "" {
+		reportOpts.contexts = []string{contextName}
+	}
+	targets, err := resolveTargets(reportOpts)
+	if err != nil {
+		return err
+	}
+	target := targets[0]
+	calc, err := newCalculator(reportOpts)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	inv, err := listInventory(ctx, target.clientset)
+	if err != nil {
+		return err
+	}
+	usage, err := collectContainerUsage(ctx, target.statsURL, time.Duration(lookbackHours)*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	recs := calc.Recommend(inv, usage, opts)
+	if namespace != "" {
+		filtered := recs[:0]
+		for _, r := range recs {
+			if r.Workload.Namespace == namespace {
+				filtered = append(filtered, r)
+			}
+		}
+		recs = filtered
+	}
+
+	if output == "json" {
+		return writeRecommendJSON(os.Stdout, recs)
+	}
+	printRecommendations(recs, lookbackHours, opts.Headroom)
+	return nil
+}
+
+// collectContainerUsage queries per-container usage from baseURL over
+// lookback.
+func collectContainerUsage(ctx context.Context, baseURL string, lookback time.Duration) (map[stats.UsageKey]stats.Usage, error) {
+	client, timeout, err := newStatsClient(baseURL, "rightsizing")
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	start := end.Add(-lookback)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return client.Usage(ctx, stats.LevelContainer, start, end, 5*time.Minute)
+}
+
+func printRecommendations(recs []cost.Recommendation, lookbackHours int, headroom float64) {
+	fmt.Printf("=== Rightsizing (p95 CPU, max memory over %dh, %.0f%% headroom) ===\n", lookbackHours, headroom*100)
+	if len(recs) == 0 {
+		fmt.Println("No recommendations: every container with usage samples already matches.")
+		return
+	}
+	fmt.Printf("%-20s %-36s %-20s %-4s %-17s %-19s %-12s\n", "NAMESPACE", "WORKLOAD", "CONTAINER", "REP", "CPU", "MEMORY", "SAVINGS $/MO")
+	var total float64
+	var capped bool
+	for _, r := range recs {
+		total += r.MonthlySavings
+		container := r.Container
+		if r.Kind == cost.ContainerKindSidecar {
+			container += " (sidecar)"
+		}
+		cpu := r.CurrentCPU.String() + " -> " + r.RecommendedCPU.String()
+		if r.CPUAtLimit {
+			cpu += "*"
+		}
+		mem := r.CurrentMemory.String() + " -> " + r.RecommendedMemory.String()
+		if r.MemoryAtLimit {
+			mem += "*"
+		}
+		capped = capped || r.CPUAtLimit || r.MemoryAtLimit
+		fmt.Printf("%-20s %-36s %-20s %-4d %-17s %-19s $%-11.2f\n",
+			truncate(r.Workload.Namespace, 20),
+			truncate(r.Workload.String(), 36),
+			truncate(container, 20),
+			r.Replicas,
+			cpu,
+			mem,
+			r.MonthlySavings)
+	}
+	fmt.Printf("%-20s %-36s %-20s %-4s %-17s %-19s $%-11.2f\n", "TOTAL", "", "", "", "", "", total)
+	if capped {
+		fmt.Println("* capped at the container's limit; raise the limit to get the full headroom.")
+	}
+}
+
+func writeRecommendJSON(w io.Writer, recs []cost.Recommendation) error {
+	patches := make([]recommendPatch, 0, len(recs))
+	for _, r := range recs {
+		requests := map[string]string{"cpu": r.RecommendedCPU.String(), "memory": r.RecommendedMemory.String()}
+		var atLimit []string
+		if r.CPUAtLimit {
+			atLimit = append(atLimit, "cpu")
+		}
+		if r.MemoryAtLimit {
+			atLimit = append(atLimit, "memory")
+		}
+		patches = append(patches, recommendPatch{
+			Kind:           r.Workload.Kind,
+			Namespace:      r.Workload.Namespace,
+			Name:           r.Workload.Name,
+			Container:      r.Container,
+			ContainerKind:  r.Kind,
+			Replicas:       r.Replicas,
+			Current:        map[string]string{"cpu": r.CurrentCPU.String(), "memory": r.CurrentMemory.String()},
+			Requests:       requests,
+			AtLimit:        atLimit,
+			MonthlySavings: r.MonthlySavings,
+			Patch:          requestsPatch(r.Workload.Kind, r.Kind, r.Container, requests),
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(patches)
+}
+
+// requestsPatch returns the strategic merge patch that sets container's
+// requests in a workload's pod template, or nil for kinds without a mutable
+// template. Sidecars are patched under initContainers.
+func requestsPatch(kind, containerKind, container string, requests map[string]string) interface{} {
+	list := "containers"
+	if containerKind == cost.ContainerKindSidecar {
+		list = "initContainers"
+	}
+	podSpec := map[string]interface{}{
+		list: []interface{}{map[string]interface{}{
+			"name":      container,
+			"resources": map[string]interface{}{"requests": requests},
+		}},
+	}
+	template := map[string]interface{}{"template": map[string]interface{}{"spec": podSpec}}
+	switch kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet":
+		return map[string]interface{}{"spec": template}
+	case "CronJob":
+		return map[string]interface{}{"spec": map[string]interface{}{"jobTemplate": map[string]interface{}{"spec": template}}}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/cost"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestWriteRecommendJSON(t *testing.T) {
+	recs := []cost.Recommendation{
+		{
+			Workload:          cost.WorkloadRef{Kind: "Deployment", Name: "web", Namespace: "shop"},
+			Container:         "app",
+			Replicas:          2,
+			CurrentCPU:        resource.MustParse("2"),
+			CurrentMemory:     resource.MustParse("4Gi"),
+			RecommendedCPU:    resource.MustParse("600m"),
+			RecommendedMemory: resource.MustParse("1200Mi"),
+			MonthlySavings:    42,
+		},
+		{
+			Workload:          cost.WorkloadRef{Kind: "Job", Name: "migrate", Namespace: "shop"},
+			Container:         "app",
+			Replicas:          1,
+			RecommendedCPU:    resource.MustParse("100m"),
+			RecommendedMemory: resource.MustParse("64Mi"),
+		},
+		{
+			Workload:          cost.WorkloadRef{Kind: "Deployment", Name: "web", Namespace: "shop"},
+			Container:         "proxy",
+			Kind:              cost.ContainerKindSidecar,
+			Replicas:          2,
+			RecommendedCPU:    resource.MustParse("500m"),
+			RecommendedMemory: resource.MustParse("64Mi"),
+			CPUAtLimit:        true,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeRecommendJSON(&buf, recs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(decoded) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(decoded))
+	}
+
+	patch, err := json.Marshal(decoded[0]["patch"])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"spec":{"template":{"spec":{"containers":[{"name":"app","resources":{"requests":{"cpu":"600m","memory":"1200Mi"}}}]}}}}`
+	if string(patch) != want {
+		t.Fatalf("unexpected patch:\n%s", patch)
+	}
+	if _, ok := decoded[1]["patch"]; ok {
+		t.Fatalf("expected no patch for a Job, got %v", decoded[1]["patch"])
+	}
+
+	sidecar, err := json.Marshal(decoded[2]["patch"])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = `{"spec":{"template":{"spec":{"initContainers":[{"name":"proxy","resources":{"requests":{"cpu":"500m","memory":"64Mi"}}}]}}}}`
+	if string(sidecar) != want {
+		t.Fatalf("unexpected sidecar patch:\n%s", sidecar)
+	}
+	if atLimit, _ := json.Marshal(decoded[2]["at_limit"]); string(atLimit) != `["cpu"]` {
+		t.Fatalf("expected cpu flagged at its limit, got %s", atLimit)
+	}
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/newman-bot/kfin/pkg/cost"
+	"github.com/newman-bot/kfin/pkg/pricing"
+	"github.com/newman-bot/kfin/pkg/stats"
+	"github.com/spf13/cobra"
+)
+
+// reportOptions are the flags shared by analyze, tui and pdf.
+type reportOptions struct {
+	costBasis         string
+	allocation        string
+	includeTerminated bool
+	prorate           bool
+	project           bool
+	contexts          []string
+	allContexts       bool
+	groupBy           string
+	chargeback        bool
+	// containerUsage fetches per-container usage so findings can flag
+	// over-requested containers.
+	containerUsage bool
+}
+
+func defaultReportOptions() reportOptions {
+	return reportOptions{
+		costBasis:         cfg.Pricing.CostBasis,
+		allocation:        cfg.Pricing.Allocation.Mode,
+		includeTerminated: cfg.Pricing.IncludeTerminated,
+		prorate:           cfg.Pricing.ProrateRuntime,
+		project:           cfg.Pricing.ProjectRuntime,
+		chargeback:        cfg.Pricing.Chargeback.Enabled,
+	}
+}
+
+func addReportFlags(cmd *cobra.Command, opts *reportOptions) {
+	cmd.Flags().StringVar(&opts.costBasis, "cost-basis", opts.costBasis, "Cost basis: requests, limits, usage or max-request-usage")
+	cmd.Flags().StringVar(&opts.allocation, "allocation", opts.allocation, "Pod cost allocation: cloud-rates or node-cost")
+	cmd.Flags().BoolVar(&opts.includeTerminated, "include-terminated", opts.includeTerminated, "Bill Succeeded and Failed pods")
+	cmd.Flags().BoolVar(&opts.prorate, "prorate", opts.prorate, "Prorate pod cost by runtime in the current month, up to now for running pods")
+	cmd.Flags().BoolVar(&opts.project, "project", opts.project, "With --prorate, project running pods to month end")
+	cmd.Flags().StringArrayVar(&opts.contexts, "context", nil, "Kubeconfig context to report on (repeatable; default: current context)")
+	cmd.Flags().BoolVar(&opts.allContexts, "all-contexts", false, "Report on every kubeconfig context")
+}
+
+// addGroupByFlag adds --group-by to the commands that print group tables.
+func addGroupByFlag(cmd *cobra.Command, opts *reportOptions) {
+	cmd.Flags().StringVar(&opts.groupBy, "group-by", "", "Group costs by namespace, workload, node or label:<key>; comma-separate for multiple levels, e.g. label:team,namespace")
+}
+
+// addChargebackFlag adds --chargeback to the commands that print the
+// chargeback table.
+func addChargebackFlag(cmd *cobra.Command, opts *reportOptions) {
+	cmd.Flags().BoolVar(&opts.chargeback, "chargeback", opts.chargeback, "Spread shared cost across tenant namespaces per pricing.chargeback")
+}
+
+// groupDimensions parses --group-by; nil means no group table.
+func groupDimensions(opts reportOptions) ([]cost.GroupDimension, error) {
+	if strings.TrimSpace(opts.groupBy) == "" {
+		return nil, nil
+	}
+	return cost.ParseGroupBy(opts.groupBy)
+}
+
+// buildReport lists the target cluster, fetches Prometheus usage from its
+// stats endpoint when the cost basis needs it, network rates, container
+// usage and node power when those are enabled, and runs the cost calculator.
+func buildReport(ctx context.Context, calc *cost.Calculator, target clusterTarget, opts reportOptions) (*cost.Report, error) {
+	inv, err := listInventory(ctx, target.clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	if calc.Basis().NeedsUsage() {
+		inv.Usage, err = collectPodUsage(ctx, target.statsURL)
+		if err != nil {
+			return nil, fmt.Errorf("cost basis %s: %w", calc.Basis(), err)
+		}
+	}
+
+	if cfg.Pricing.Network.Enabled {
+		inv.NetworkTransmit, err = collectPodNetwork(ctx, target.statsURL)
+		if err != nil {
+			log.Printf("warning: network cost disabled: %v", err)
+		}
+	}
+
+	if opts.containerUsage && target.statsURL != "" {
+		inv.ContainerUsage, err = collectContainerUsage(ctx, target.statsURL, time.Duration(cfg.Recommend.LookbackHours)*time.Hour)
+		if err != nil {
+			log.Printf("warning: over-requested container findings disabled: %v", err)
+		}
+	}
+
+	if power := cfg.Pricing.Power.Source; cost.PowerSourceNeedsUtilization(power) || cost.PowerSourceIsMeasured(power) {
+		inv.NodePower, err = collectNodePower(ctx, target.statsURL)
+		if err != nil {
+			log.Printf("warning: power model falling back to static watts: %v", err)
+		}
+	}
+
+	return calc.Calculate(inv), nil
+}
+
+// newCalculator builds a cost calculator from cfg and opts. When
+// pricing.mcp.command is set it tries MCP rates first and falls back to
+// pricing.cloud on failure.
+func newCalculator(opts reportOptions) (*cost.Calculator, error) {
+	if opts.project && !opts.prorate {
+		return nil, fmt.Errorf("--project requires --prorate")
+	}
+	calcCfg := *cfg
+	calcCfg.Pricing.CostBasis = opts.costBasis
+	calcCfg.Pricing.Allocation.Mode = opts.allocation
+	calcCfg.Pricing.IncludeTerminated = opts.includeTerminated
+	calcCfg.Pricing.ProrateRuntime = opts.prorate
+	calcCfg.Pricing.ProjectRuntime = opts.project
+	calcCfg.Pricing.Chargeback.Enabled = opts.chargeback
+
+	base := pricing.NewStaticProvider(cfg.Pricing.Cloud.CPUPerHour, cfg.Pricing.Cloud.MemPerGBHour)
+
+	cmd := strings.TrimSpace(cfg.Pricing.MCP.Command)
+	if cmd != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		calc, err := cost.NewCalculator(ctx, &calcCfg, pricing.NewMCPProvider(cmd, cfg.Pricing.MCP.Args))
+		if err == nil {
+			return calc, nil
+		}
+		log.Printf("warning: mcp pricing failed, falling back to config rates: %v", err)
+	}
+
+	return cost.NewCalculator(context.Background(), &calcCfg, base)
+}
+
+// collectPodUsage queries per-pod average usage from baseURL over the default
+// lookback.
+func collectPodUsage(ctx context.Context, baseURL string) (map[stats.PodKey]stats.Usage, error) {
+	client, timeout, err := newStatsClient(baseURL, "usage-based costing")
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	start := end.Add(-time.Duration(cfg.Stats.DefaultLookbackHours) * time.Hour)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return client.PodUsage(ctx, start, end, 5*time.Minute)
+}
+
+// collectPodNetwork queries per-pod transmit rates over the default lookback.
+func collectPodNetwork(ctx context.Context, baseURL string) (map[stats.PodKey]stats.Aggregate, error) {
+	client, timeout, err := newStatsClient(baseURL, "network costing")
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	start := end.Add(-time.Duration(cfg.Stats.DefaultLookbackHours) * time.Hour)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return client.PodNetworkTransmit(ctx, start, end, 5*time.Minute)
+}
+
+// collectNodePower queries node CPU utilization and, for the kepler and
+// hwmon power sources, measured node power over the default lookback. Nodes
+// without measurements fall back to the utilization model.
+func collectNodePower(ctx context.Context, baseURL string) (map[string]cost.NodePower, error) {
+	client, timeout, err := newStatsClient(baseURL, "the power model")
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	start := end.Add(-time.Duration(cfg.Stats.DefaultLookbackHours) * time.Hour)
+	// Each query gets its own timeout so a slow one does not starve the other.
+	queryCtx := func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	power := cfg.Pricing.Power
+	utilQuery := strings.TrimSpace(power.UtilizationQuery)
+	if utilQuery == "" {
+		utilQuery = stats.NodeCPUUtilizationQuery
+	}
+	utilCtx, cancel := queryCtx()
+	util, err := client.NodeSeries(utilCtx, utilQuery, start, end, 5*time.Minute)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("query node cpu utilization: %w", err)
+	}
+
+	out := make(map[string]cost.NodePower, len(util))
+	for node, agg := range util {
+		out[node] = cost.NodePower{Utilization: agg.Avg, HasUtilization: true}
+	}
+
+	if !cost.PowerSourceIsMeasured(power.Source) {
+		return out, nil
+	}
+	measuredQuery := strings.TrimSpace(power.MeasuredQuery)
+	if measuredQuery == "" {
+		measuredQuery, err = stats.MeasuredPowerQuery(power.Source)
+		if err != nil {
+			return out, err
+		}
+	}
+	measuredCtx, cancel := queryCtx()
+	measured, err := client.NodeSeries(measuredCtx, measuredQuery, start, end, 5*time.Minute)
+	cancel()
+	if err != nil {
+		return out, fmt.Errorf("query %s node power: %w", power.Source, err)
+	}
+	for node, agg := range measured {
+		p := out[node]
+		p.MeasuredWatts = agg.Avg
+		out[node] = p
+	}
+	return out, nil
+}
+
+// newStatsClient builds a Prometheus client for baseURL. purpose names the
+// feature that needs it in the error when no endpoint is set.
+func newStatsClient(baseURL, purpose string) (*stats.Client, time.Duration, error) {
+	if baseURL == "" {
+		return nil, 0, fmt.Errorf("stats.base_url is empty; %s needs a Prometheus endpoint", purpose)
+	}
+
+	timeout := time.Duration(cfg.Stats.QueryTimeoutSeconds) * time.Second
+	client, err := stats.NewClient(baseURL, timeout)
+	if err != nil {
+		return nil, 0, err
+	}
+	return client, timeout, nil
+}
@@ -4,85 +4,86 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strings"
+	"sync"
 	"time"
 
+	"github.com/newman-bot/kfin/pkg/cost"
 	"github.com/newman-bot/kfin/pkg/pdf"
 	"github.com/newman-bot/kfin/pkg/stats"
 	"github.com/newman-bot/kfin/pkg/tui"
 	"github.com/spf13/cobra"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 func TuiCmd() *cobra.Command {
-	return &cobra.Command{
+	opts := defaultReportOptions()
+	tuiCmd := &cobra.Command{
 		Use:   "tui",
 		Short: "Open interactive TUI dashboard",
 		Run: func(cmd *cobra.Command, args []string) {
-			runTui()
+			runTui(opts)
 		},
 	}
+	addReportFlags(tuiCmd, &opts)
+	addGroupByFlag(tuiCmd, &opts)
+	addChargebackFlag(tuiCmd, &opts)
+	return tuiCmd
 }
 
 func PdfCmd() *cobra.Command {
 	var output string
+	opts := defaultReportOptions()
 	pdfCmd := &cobra.Command{
 		Use:   "pdf",
 		Short: "Export cost report to PDF",
 		Run: func(cmd *cobra.Command, args []string) {
-			runPdf(output)
+			runPdf(output, opts)
 		},
 	}
 	pdfCmd.Flags().StringVarP(&output, "output", "o", "kfin-report.pdf", "Output PDF filename")
+	addReportFlags(pdfCmd, &opts)
+	addGroupByFlag(pdfCmd, &opts)
+	addChargebackFlag(pdfCmd, &opts)
 	return pdfCmd
 }
 
-func runTui() {
-	clientset, err := getClientset()
+func runTui(opts reportOptions) {
+	dims, err := groupDimensions(opts)
 	if err != nil {
-		log.Fatalf("Failed to connect to cluster: %v", err)
+		log.Fatalf("Invalid --group-by: %v", err)
 	}
-	resolveUsageRates()
-	contextName, clusterName := getKubeContextDetails()
-
-	ctx := context.Background()
-
-	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		log.Fatalf("Failed to list pods: %v", err)
-	}
-
-	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	opts.containerUsage = true
+	reports, err := buildClusterReports(context.Background(), opts)
 	if err != nil {
-		log.Fatalf("Failed to list nodes: %v", err)
-	}
-
-	// Collect data
-	podCosts := collectPodCosts(pods.Items)
-	nodeInfo := collectNodeInfo(nodes.Items)
-	hardwareCost, elecCost, controlPlaneCost := calculateClusterCosts(nodes.Items)
-	statsFreshness := collectStatsFreshness()
-
-	data := tui.ReportData{
-		PodCosts:         podCosts,
-		HardwareCost:     hardwareCost,
-		ElecCost:         elecCost,
-		ControlPlaneCost: controlPlaneCost,
-		TotalCost:        hardwareCost + elecCost + controlPlaneCost,
-		Nodes:            nodeInfo,
-		ContextName:      contextName,
-		ClusterName:      clusterName,
-		PricingSource:    activeUsageRatesSource,
-		StatsFreshness:   statsFreshness,
-	}
-
-	tui.ShowDashboard(data)
+		log.Fatalf("Failed to build cost report: %v", err)
+	}
+
+	clusters := make([]tui.ReportData, len(reports))
+	var wg sync.WaitGroup
+	for i, r := range reports {
+		wg.Add(1)
+		go func(i int, r cost.ClusterReport) {
+			defer wg.Done()
+			clusters[i] = tui.ReportData{
+				Report:         r.Report,
+				ContextName:    r.Context,
+				ClusterName:    r.Cluster,
+				StatsFreshness: collectStatsFreshness(statsBaseURL(r.Context)),
+				Forecast:       collectForecast(statsBaseURL(r.Context), r.Report),
+				GroupBy:        dims,
+			}
+		}(i, r)
+	}
+	wg.Wait()
+
+	if len(clusters) == 1 {
+		tui.ShowDashboard(clusters[0])
+		return
+	}
+	tui.ShowFleet(tui.FleetData{Fleet: cost.NewFleet(reports), Clusters: clusters})
 }
 
-func collectStatsFreshness() tui.StatsFreshness {
-	baseURL := strings.TrimSpace(cfg.Stats.BaseURL)
+func collectStatsFreshness(baseURL string) tui.StatsFreshness {
 	if baseURL == "" {
 		return tui.StatsFreshness{
 			Ready: false,
@@ -140,44 +141,81 @@ func collectStatsFreshness() tui.StatsFreshness {
 	}
 }
 
-func runPdf(output string) {
-	clientset, err := getClientset()
+// collectForecast fits a usage cost forecast to the last four weeks at the
+// report's rates and rebases it onto the report's TotalCost, so the header's
+// projections grow with usage but are on the same basis as its monthly
+// figure. It is best effort: without Prometheus, enough history or a usage
+// trend large enough to rebase, the header shows no forecast.
+func collectForecast(baseURL string, report *cost.Report) *cost.Forecast {
+	if baseURL == "" {
+		return nil
+	}
+	client, timeout, err := newStatsClient(baseURL, "forecast")
 	if err != nil {
-		log.Fatalf("Failed to connect to cluster: %v", err)
+		log.Printf("warning: %v", err)
+		return nil
 	}
-	resolveUsageRates()
-	contextName, clusterName := getKubeContextDetails()
 
-	ctx := context.Background()
+	end := time.Now()
+	start := end.Add(-forecastLookbackHours * time.Hour)
+	// Each query gets its own timeout so a slow one does not starve the other.
+	queryCtx := func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), timeout)
+	}
 
-	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	ctx, cancel := queryCtx()
+	cpuResp, err := client.QueryRange(ctx, defaultCPUQuery, start, end, forecastStep)
+	cancel()
 	if err != nil {
-		log.Fatalf("Failed to list pods: %v", err)
+		log.Printf("warning: forecast cpu query failed: %v", err)
+		return nil
 	}
-
-	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	ctx, cancel = queryCtx()
+	memResp, err := client.QueryRange(ctx, defaultMemQuery, start, end, forecastStep)
+	cancel()
 	if err != nil {
-		log.Fatalf("Failed to list nodes: %v", err)
+		log.Printf("warning: forecast memory query failed: %v", err)
+		return nil
 	}
+	f, err := forecastUsageCost(cpuResp, memResp, report.Rates, end, true)
+	if err == nil {
+		f, err = f.ScaleTo(report.TotalCost)
+	}
+	if err != nil {
+		log.Printf("warning: no cost forecast: %v", err)
+		return nil
+	}
+	return f
+}
 
-	// Collect data
-	podCosts := collectPodCostsForPdf(pods.Items)
-	nodeInfo := collectNodeInfoForPdf(nodes.Items)
-	hardwareCost, elecCost, controlPlaneCost := calculateClusterCosts(nodes.Items)
+func runPdf(output string, opts reportOptions) {
+	dims, err := groupDimensions(opts)
+	if err != nil {
+		log.Fatalf("Invalid --group-by: %v", err)
+	}
+	reports, err := buildClusterReports(context.Background(), opts)
+	if err != nil {
+		log.Fatalf("Failed to build cost report: %v", err)
+	}
 
-	data := pdf.ReportData{
-		PodCosts:         podCosts,
-		HardwareCost:     hardwareCost,
-		ElecCost:         elecCost,
-		ControlPlaneCost: controlPlaneCost,
-		TotalCost:        hardwareCost + elecCost + controlPlaneCost,
-		Nodes:            nodeInfo,
-		GeneratedAt:      time.Now(),
-		ContextName:      contextName,
-		ClusterName:      clusterName,
+	generatedAt := time.Now()
+	clusters := make([]pdf.ReportData, 0, len(reports))
+	for _, r := range reports {
+		clusters = append(clusters, pdf.ReportData{
+			Report:      r.Report,
+			GeneratedAt: generatedAt,
+			ContextName: r.Context,
+			ClusterName: r.Cluster,
+			GroupBy:     dims,
+		})
 	}
 
-	if err := pdf.Generate(data, output); err != nil {
+	if len(clusters) == 1 {
+		err = pdf.Generate(clusters[0], output)
+	} else {
+		err = pdf.GenerateFleet(pdf.FleetData{Fleet: cost.NewFleet(reports), GeneratedAt: generatedAt, Clusters: clusters}, output)
+	}
+	if err != nil {
 		log.Fatalf("Failed to generate PDF: %v", err)
 	}
 
@@ -185,36 +223,10 @@ func runPdf(output string) {
 }
 
 func getClientset() (*kubernetes.Clientset, error) {
-	kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		clientcmd.NewDefaultClientConfigLoadingRules(),
-		&clientcmd.ConfigOverrides{},
-	)
-
-	k8sConfig, err := kubeconfig.ClientConfig()
+	k8sConfig, err := kubeConfigFor("").ClientConfig()
 	if err != nil {
 		return nil, err
 	}
 
 	return kubernetes.NewForConfig(k8sConfig)
 }
-
-func getKubeContextDetails() (string, string) {
-	kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		clientcmd.NewDefaultClientConfigLoadingRules(),
-		&clientcmd.ConfigOverrides{},
-	)
-	rawCfg, err := kubeconfig.RawConfig()
-	if err != nil {
-		return "unknown", "unknown"
-	}
-
-	contextName := rawCfg.CurrentContext
-	if contextName == "" {
-		contextName = "unknown"
-	}
-	clusterName := "unknown"
-	if ctx, ok := rawCfg.Contexts[rawCfg.CurrentContext]; ok && ctx != nil && ctx.Cluster != "" {
-		clusterName = ctx.Cluster
-	}
-	return contextName, clusterName
-}
@@ -46,6 +46,10 @@ func init() {
 	rootCmd.AddCommand(cmd.StatusCmd())
 	rootCmd.AddCommand(tuiCmd)
 	rootCmd.AddCommand(cmd.PdfCmd())
+	rootCmd.AddCommand(cmd.NamespacesCmd())
+	rootCmd.AddCommand(cmd.RecommendCmd())
+	rootCmd.AddCommand(cmd.FindingsCmd())
+	rootCmd.AddCommand(cmd.CheckCmd())
 }
 
 func main() {
@@ -7,23 +7,110 @@ import (
 )
 
 type Config struct {
-	Pricing PricingConfig `yaml:"pricing"`
-	Stats   StatsConfig   `yaml:"stats"`
+	Pricing   PricingConfig   `yaml:"pricing"`
+	Stats     StatsConfig     `yaml:"stats"`
+	Recommend RecommendConfig `yaml:"recommend"`
+	Findings  FindingsConfig  `yaml:"findings"`
+	Budgets   BudgetsConfig   `yaml:"budgets"`
 }
 
 type PricingConfig struct {
-	HardwareMonthlyPerGB  float64            `yaml:"hardware_monthly_per_gb"`
-	ElectricityRate       float64            `yaml:"electricity_rate"` // $/kWh
-	WattsPerNode          float64            `yaml:"watts_per_node"`   // watts
-	InstanceMonthlyByType map[string]float64 `yaml:"instance_monthly_by_type"`
-	EKS                   EKSPricingConfig   `yaml:"eks"`
-	MCP                   MCPPricingConfig   `yaml:"mcp"`
-	Cloud                 CloudPricing       `yaml:"cloud"`
+	HardwareMonthlyPerGB   float64                       `yaml:"hardware_monthly_per_gb"`
+	HardwareMonthlyPerCore float64                       `yaml:"hardware_monthly_per_core"`
+	HardwareProfiles       []HardwareProfile             `yaml:"hardware_profiles"`
+	ElectricityRate        float64                       `yaml:"electricity_rate"` // $/kWh
+	WattsPerNode           float64                       `yaml:"watts_per_node"`   // watts
+	Power                  PowerConfig                   `yaml:"power"`
+	InstanceMonthlyByType  map[string]float64            `yaml:"instance_monthly_by_type"`
+	SpotMonthlyByType      map[string]float64            `yaml:"spot_monthly_by_type"`
+	SpotMonthlyByZone      map[string]map[string]float64 `yaml:"spot_monthly_by_zone"` // zone -> instance type -> $/month
+	SpotDiscount           float64                       `yaml:"spot_discount"`        // fraction off on-demand for spot types without a spot price
+	CostBasis              string                        `yaml:"cost_basis"`           // requests, limits, usage, max-request-usage
+	Allocation             AllocationConfig              `yaml:"allocation"`
+	Chargeback             ChargebackConfig              `yaml:"chargeback"`
+	IncludeTerminated      bool                          `yaml:"include_terminated"` // bill Succeeded/Failed pods
+	ProrateRuntime         bool                          `yaml:"prorate_runtime"`    // scale pod cost by runtime this month
+	ProjectRuntime         bool                          `yaml:"project_runtime"`    // with prorate_runtime, bill running pods to month end
+	Storage                StoragePricing                `yaml:"storage"`
+	LoadBalancers          LoadBalancerPricing           `yaml:"load_balancers"`
+	Network                NetworkPricing                `yaml:"network"`
+	EKS                    EKSPricingConfig              `yaml:"eks"`
+	GKE                    ControlPlanePricing           `yaml:"gke"`
+	AKS                    ControlPlanePricing           `yaml:"aks"`
+	K3s                    ControlPlanePricing           `yaml:"k3s"`
+	RKE2                   ControlPlanePricing           `yaml:"rke2"`
+	MCP                    MCPPricingConfig              `yaml:"mcp"`
+	Cloud                  CloudPricing                  `yaml:"cloud"`
 }
 
 type CloudPricing struct {
-	CPUPerHour   float64 `yaml:"cpu_per_hour"`    // $/vCPU/hour
-	MemPerGBHour float64 `yaml:"mem_per_gb_hour"` // $/GB/hour
+	CPUPerHour      float64            `yaml:"cpu_per_hour"`      // $/vCPU/hour
+	MemPerGBHour    float64            `yaml:"mem_per_gb_hour"`   // $/GB/hour
+	ExtendedPerHour map[string]float64 `yaml:"extended_per_hour"` // $/unit/hour by resource name; $/GB/hour for hugepages-* and ephemeral-storage
+}
+
+// HardwareProfile prices the hardware of nodes listed in Nodes or matching
+// Selector. A purchase price with amortization months takes precedence over
+// the per-GB and per-core rates.
+type HardwareProfile struct {
+	Name               string   `yaml:"name"`
+	Nodes              []string `yaml:"nodes"`               // node names
+	Selector           string   `yaml:"selector"`            // label selector, e.g. "kfin.io/hw=nuc,kubernetes.io/arch=amd64"
+	MonthlyPerGB       float64  `yaml:"monthly_per_gb"`      // $/GB of memory capacity/month
+	MonthlyPerCore     float64  `yaml:"monthly_per_core"`    // $/core of CPU capacity/month
+	PurchasePrice      float64  `yaml:"purchase_price"`      // $ per node
+	AmortizationMonths int      `yaml:"amortization_months"` // months the purchase price is spread over
+}
+
+// PowerConfig models node power draw. Nodes matching a profile draw between
+// IdleWatts and MaxWatts by CPU utilization; other nodes draw watts_per_node.
+type PowerConfig struct {
+	PUE              float64        `yaml:"pue"`               // facility overhead multiplier, 1 = none
+	Source           string         `yaml:"source"`            // static, utilization, kepler or hwmon
+	UtilizationQuery string         `yaml:"utilization_query"` // overrides the node CPU utilization PromQL
+	MeasuredQuery    string         `yaml:"measured_query"`    // overrides the kepler/hwmon watts PromQL
+	Profiles         []PowerProfile `yaml:"profiles"`
+}
+
+type PowerProfile struct {
+	Name      string   `yaml:"name"`
+	Nodes     []string `yaml:"nodes"`    // node names
+	Selector  string   `yaml:"selector"` // label selector
+	IdleWatts float64  `yaml:"idle_watts"`
+	MaxWatts  float64  `yaml:"max_watts"`
+}
+
+type StoragePricing struct {
+	DefaultPerGBMonth float64            `yaml:"default_per_gb_month"` // $/GB/month for classes not in by_class
+	ByClass           map[string]float64 `yaml:"by_class"`             // $/GB/month by StorageClass name
+}
+
+type LoadBalancerPricing struct {
+	PerHour        map[string]float64 `yaml:"per_hour"`         // $/hour by type: classic, nlb, alb
+	DefaultPerHour float64            `yaml:"default_per_hour"` // $/hour for LoadBalancer Services of unknown type
+	IncludeIngress bool               `yaml:"include_ingress"`  // price ALBs provisioned for alb Ingresses
+}
+
+type NetworkPricing struct {
+	Enabled             bool    `yaml:"enabled"`                // query container_network_transmit_bytes_total from stats.base_url
+	InternetEgressPerGB float64 `yaml:"internet_egress_per_gb"` // $/GB leaving the cloud
+	CrossZonePerGB      float64 `yaml:"cross_zone_per_gb"`      // $/GB between availability zones
+	InZonePerGB         float64 `yaml:"in_zone_per_gb"`         // $/GB within a zone
+	InternetFraction    float64 `yaml:"internet_fraction"`      // share of transmitted bytes assumed to leave the cluster
+}
+
+type AllocationConfig struct {
+	Mode           string  `yaml:"mode"`             // cloud-rates, node-cost
+	CPUMemoryRatio float64 `yaml:"cpu_memory_ratio"` // node cost split CPU:memory as ratio:1
+}
+
+// ChargebackConfig spreads shared cost (shared namespaces, idle, control
+// plane and unallocated node cost) across the remaining tenant namespaces.
+type ChargebackConfig struct {
+	Enabled          bool               `yaml:"enabled"`
+	SharedNamespaces []string           `yaml:"shared_namespaces"` // namespaces billed to every tenant, e.g. kube-system
+	Method           string             `yaml:"method"`            // even, proportional (to direct cost) or weights
+	Weights          map[string]float64 `yaml:"weights"`           // namespace -> weight, for method weights; unlisted namespaces get 0
 }
 
 type MCPPricingConfig struct {
@@ -32,13 +119,53 @@ type MCPPricingConfig struct {
 }
 
 type EKSPricingConfig struct {
+	ControlPlanePerHour float64        `yaml:"control_plane_per_hour"`
+	Fargate             FargatePricing `yaml:"fargate"`
+}
+
+// ControlPlanePricing prices a distribution's control plane, applied once per
+// detected cluster.
+type ControlPlanePricing struct {
 	ControlPlanePerHour float64 `yaml:"control_plane_per_hour"`
 }
 
+type FargatePricing struct {
+	VCPUPerHour float64 `yaml:"vcpu_per_hour"` // $/vCPU/hour
+	GBPerHour   float64 `yaml:"gb_per_hour"`   // $/GB/hour
+}
+
 type StatsConfig struct {
-	BaseURL              string `yaml:"base_url"`
-	QueryTimeoutSeconds  int    `yaml:"query_timeout_seconds"`
-	DefaultLookbackHours int    `yaml:"default_lookback_hours"`
+	BaseURL              string            `yaml:"base_url"`
+	BaseURLByContext     map[string]string `yaml:"base_url_by_context"` // kubeconfig context -> Prometheus URL, for fleet reports
+	QueryTimeoutSeconds  int               `yaml:"query_timeout_seconds"`
+	DefaultLookbackHours int               `yaml:"default_lookback_hours"`
+}
+
+// RecommendConfig tunes kfin recommend: requests are sized to p95 CPU and
+// max memory over the lookback, plus headroom.
+type RecommendConfig struct {
+	LookbackHours int     `yaml:"lookback_hours"` // usage window
+	Headroom      float64 `yaml:"headroom"`       // fraction added on top of observed usage, e.g. 0.2
+	MinCPU        string  `yaml:"min_cpu"`        // lowest recommended CPU request, e.g. 10m
+	MinMemory     string  `yaml:"min_memory"`     // lowest recommended memory request, e.g. 32Mi
+}
+
+// FindingsConfig sets the thresholds of kfin findings.
+type FindingsConfig struct {
+	OverRequestFactor float64 `yaml:"over_request_factor"` // flag requests at least this many times p95 CPU or max memory
+	LowUtilization    float64 `yaml:"low_utilization"`     // flag nodes whose CPU and memory requests are both below this fraction of allocatable
+	PendingMinutes    int     `yaml:"pending_minutes"`     // flag pods Pending for longer than this
+}
+
+// BudgetsConfig sets monthly dollar limits. Namespace budgets take precedence
+// over kfin.io/budget annotations. Warn and Critical are fractions of a
+// budget; kfin check fails at Critical.
+type BudgetsConfig struct {
+	Warn       float64                       `yaml:"warn"`       // burn that warns, e.g. 0.8
+	Critical   float64                       `yaml:"critical"`   // burn that breaches, e.g. 1.0
+	Cluster    float64                       `yaml:"cluster"`    // $/month for the whole cluster; 0 = none
+	Namespaces map[string]float64            `yaml:"namespaces"` // namespace -> $/month
+	Labels     map[string]map[string]float64 `yaml:"labels"`     // label key -> value -> $/month
 }
 
 func Load(path string) (*Config, error) {
@@ -65,20 +192,70 @@ func Load(path string) (*Config, error) {
 func DefaultConfig() *Config {
 	return &Config{
 		Pricing: PricingConfig{
-			HardwareMonthlyPerGB:  0.26,
-			ElectricityRate:       0.12,
-			WattsPerNode:          15,
+			HardwareMonthlyPerGB: 0.26,
+			ElectricityRate:      0.12,
+			WattsPerNode:         15,
+			Power: PowerConfig{
+				PUE:    1,
+				Source: "static",
+			},
 			InstanceMonthlyByType: map[string]float64{},
+			SpotMonthlyByType:     map[string]float64{},
+			SpotMonthlyByZone:     map[string]map[string]float64{},
+			CostBasis:             "requests",
+			Allocation: AllocationConfig{
+				Mode:           "cloud-rates",
+				CPUMemoryRatio: 1,
+			},
+			Chargeback: ChargebackConfig{
+				SharedNamespaces: []string{"kube-system", "monitoring"},
+				Method:           "proportional",
+				Weights:          map[string]float64{},
+			},
+			Storage: StoragePricing{
+				DefaultPerGBMonth: 0.10,
+				ByClass:           map[string]float64{},
+			},
+			LoadBalancers: LoadBalancerPricing{
+				PerHour: map[string]float64{
+					"classic": 0.025,
+					"nlb":     0.0225,
+					"alb":     0.0225,
+				},
+			},
+			Network: NetworkPricing{
+				InternetEgressPerGB: 0.09,
+				CrossZonePerGB:      0.02,
+				InZonePerGB:         0,
+				InternetFraction:    0.1,
+			},
 			EKS: EKSPricingConfig{
 				ControlPlanePerHour: 0.10,
+				Fargate: FargatePricing{
+					VCPUPerHour: 0.04048,
+					GBPerHour:   0.004445,
+				},
+			},
+			GKE: ControlPlanePricing{
+				ControlPlanePerHour: 0.10,
+			},
+			AKS: ControlPlanePricing{
+				ControlPlanePerHour: 0,
+			},
+			K3s: ControlPlanePricing{
+				ControlPlanePerHour: 0,
+			},
+			RKE2: ControlPlanePricing{
+				ControlPlanePerHour: 0,
 			},
 			MCP: MCPPricingConfig{
 				Command: "",
 				Args:    []string{},
 			},
 			Cloud: CloudPricing{
-				CPUPerHour:   0.025,
-				MemPerGBHour: 0.006,
+				CPUPerHour:      0.025,
+				MemPerGBHour:    0.006,
+				ExtendedPerHour: map[string]float64{},
 			},
 		},
 		Stats: StatsConfig{
@@ -86,5 +263,22 @@ func DefaultConfig() *Config {
 			QueryTimeoutSeconds:  15,
 			DefaultLookbackHours: 24,
 		},
+		Recommend: RecommendConfig{
+			LookbackHours: 168,
+			Headroom:      0.2,
+			MinCPU:        "10m",
+			MinMemory:     "32Mi",
+		},
+		Findings: FindingsConfig{
+			OverRequestFactor: 2,
+			LowUtilization:    0.3,
+			PendingMinutes:    15,
+		},
+		Budgets: BudgetsConfig{
+			Warn:       0.8,
+			Critical:   1.0,
+			Namespaces: map[string]float64{},
+			Labels:     map[string]map[string]float64{},
+		},
 	}
 }
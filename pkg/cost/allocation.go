@@ -0,0 +1,200 @@
+package cost
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// AllocationMode selects how pod cost is derived.
+type AllocationMode string
+
+const (
+	// AllocationCloudRates prices pods at pricing.cloud (or MCP) usage rates,
+	// independently of what the nodes cost.
+	AllocationCloudRates AllocationMode = "cloud-rates"
+	// AllocationNodeCost splits each node's hardware and electricity cost
+	// across the pods scheduled on it; the unreserved share is idle.
+	AllocationNodeCost AllocationMode = "node-cost"
+)
+
+// IdleNamespace is the namespace bucket that holds idle node cost in
+// Report.Namespaces.
+const IdleNamespace = "__idle__"
+
+// ParseAllocationMode validates an allocation mode name. An empty string means
+// cloud-rates.
+func ParseAllocationMode(s string) (AllocationMode, error) {
+	switch m := AllocationMode(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return AllocationCloudRates, nil
+	case AllocationCloudRates, AllocationNodeCost:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid allocation mode %q (expected: cloud-rates or node-cost)", s)
+	}
+}
+
+// NamespaceCost is the summed cost of all pods in a namespace. Extended sums
+// the pods' priced extended resource requests. StorageCost is the part of
+// Cost that comes from the namespace's bound volume claims, LoadBalancerCost
+// the part from its load balancers.
+type NamespaceCost struct {
+	Name             string
+	Pods             int
+	Cost             float64
+	StorageCost      float64
+	LoadBalancerCost float64
+	Extended         corev1.ResourceList
+	// From the kfin.io/owner, kfin.io/cost-center and kfin.io/budget
+	// annotations; empty (Budget 0) when unset. A budgets.namespaces entry
+	// replaces the annotated Budget.
+	Owner      string
+	CostCenter string
+	Budget     float64
+}
+
+// allocateNodeCosts splits each node's cost into a CPU and a memory pool by
+// the configured ratio and divides each pool by reserved share of the node's
+// allocatable capacity. Whatever is not reserved is the node's idle cost. In
+// node-cost mode the pods' shares replace their cloud-rate cost, so pod costs
+// plus idle add up to the nodes' total cost. Fargate pods keep their own
+// price and reserve nothing.
+//
+// Idle in cloud-rates mode is reserved by the effective requests of running
+// pods, matching the scheduler. Node-cost mode reserves by the quantities
+// billed under the cost basis so usage-based modes still charge BestEffort
+// pods; each reservation is weighted by the pod's runtime fraction, so
+// finished pods kept in the report only hold the node for as long as they ran.
+func (c *Calculator) allocateNodeCosts(report *Report) {
+	byNode := make(map[string][]int)
+	for i, pod := range report.Pods {
+		if pod.NodeName == "" || pod.Fargate {
+			continue
+		}
+		if c.allocation != AllocationNodeCost && isTerminated(pod.Phase) {
+			continue
+		}
+		byNode[pod.NodeName] = append(byNode[pod.NodeName], i)
+	}
+
+	reserved := func(pod PodCost) (resource.Quantity, resource.Quantity) {
+		if c.allocation == AllocationNodeCost {
+			return scaleQuantity(pod.CPU, pod.RuntimeFraction), scaleQuantity(pod.Memory, pod.RuntimeFraction)
+		}
+		return pod.Requests.Effective.Cpu().DeepCopy(), pod.Requests.Effective.Memory().DeepCopy()
+	}
+
+	// The node's price already covers any accelerators it carries, so
+	// extended resources are not billed on top of the node share.
+	if c.allocation == AllocationNodeCost {
+		for i := range report.Pods {
+			if report.Pods[i].Fargate {
+				continue
+			}
+			report.Pods[i].Cost = 0
+			report.Pods[i].ExtendedCost = 0
+		}
+	}
+
+	cpuWeight := c.cpuMemoryRatio / (c.cpuMemoryRatio + 1)
+	report.IdleCost = 0
+	for i := range report.Nodes {
+		node := &report.Nodes[i]
+		node.RequestedCPU = resource.Quantity{}
+		node.RequestedMemory = resource.Quantity{}
+		for _, idx := range byNode[node.Name] {
+			cpu, mem := reserved(report.Pods[idx])
+			node.RequestedCPU.Add(cpu)
+			node.RequestedMemory.Add(mem)
+		}
+
+		node.IdleCPU = remaining(node.AllocatableCPU, node.RequestedCPU)
+		node.IdleMemory = remaining(node.AllocatableMemory, node.RequestedMemory)
+
+		// Overcommitted nodes divide by what was reserved so the pods' shares
+		// never exceed the node's cost.
+		cpuDenom := maxMilli(node.AllocatableCPU, node.RequestedCPU)
+		memDenom := maxValue(node.AllocatableMemory, node.RequestedMemory)
+		cpuPool := node.TotalCost * cpuWeight
+		memPool := node.TotalCost - cpuPool
+
+		node.IdleCost = cpuPool*fraction(node.IdleCPU.MilliValue(), cpuDenom) +
+			memPool*fraction(node.IdleMemory.Value(), memDenom)
+		report.IdleCost += node.IdleCost
+
+		if c.allocation != AllocationNodeCost {
+			continue
+		}
+		for _, idx := range byNode[node.Name] {
+			cpu, mem := reserved(report.Pods[idx])
+			report.Pods[idx].Cost = cpuPool*fraction(cpu.MilliValue(), cpuDenom) +
+				memPool*fraction(mem.Value(), memDenom)
+		}
+	}
+}
+
+func maxMilli(a, b resource.Quantity) int64 {
+	if a.MilliValue() > b.MilliValue() {
+		return a.MilliValue()
+	}
+	return b.MilliValue()
+}
+
+func maxValue(a, b resource.Quantity) int64 {
+	if a.Value() > b.Value() {
+		return a.Value()
+	}
+	return b.Value()
+}
+
+// summarizeNamespaces rolls pod costs up to namespaces, highest cost first.
+// Bound claims no pod mounts and load balancers are charged to their
+// namespace directly. In node-cost mode the idle bucket is appended so the
+// rollup accounts for every dollar of node cost.
+func summarizeNamespaces(report *Report) []NamespaceCost {
+	byNS := make(map[string]*NamespaceCost)
+	get := func(name string) *NamespaceCost {
+		item, ok := byNS[name]
+		if !ok {
+			item = &NamespaceCost{Name: name, Extended: corev1.ResourceList{}}
+			byNS[name] = item
+		}
+		return item
+	}
+	for _, p := range report.Pods {
+		item := get(p.Namespace)
+		item.Pods++
+		item.Cost += p.Cost
+		item.StorageCost += p.StorageCost
+		addResources(item.Extended, p.Extended)
+	}
+	for ns, cost := range unmountedClaimCosts(report.Volumes) {
+		item := get(ns)
+		item.Cost += cost
+		item.StorageCost += cost
+	}
+	for _, lb := range report.LoadBalancers {
+		item := get(lb.Namespace)
+		item.Cost += lb.Cost
+		item.LoadBalancerCost += lb.Cost
+	}
+
+	out := make([]NamespaceCost, 0, len(byNS)+1)
+	for _, ns := range byNS {
+		out = append(out, *ns)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Cost == out[j].Cost {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].Cost > out[j].Cost
+	})
+	if report.Allocation == AllocationNodeCost {
+		out = append(out, NamespaceCost{Name: IdleNamespace, Cost: report.IdleCost})
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package cost
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/pricing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestParseAllocationMode(t *testing.T) {
+	if m, err := ParseAllocationMode(""); err != nil || m != AllocationCloudRates {
+		t.Fatalf("expected empty mode to mean cloud-rates, got %q (%v)", m, err)
+	}
+	if _, err := ParseAllocationMode("even"); err == nil {
+		t.Fatalf("expected error for invalid mode")
+	}
+}
+
+func TestCalculate_NodeCostAllocation(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.Allocation.Mode = string(AllocationNodeCost)
+	cfg.Pricing.Allocation.CPUMemoryRatio = 3
+	cfg.Pricing.InstanceMonthlyByType = map[string]float64{"m5.large": 100}
+	cfg.Pricing.WattsPerNode = 0
+	calc, err := NewCalculator(context.Background(), cfg, pricing.NewStaticProvider(0.02, 0.005))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	node := testNode("worker-1", "8Gi", map[string]string{nodeInstanceTypeLabel: "m5.large"})
+	node.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+	}
+	api := testPod("web", "api", testContainer("app", "2", "2Gi"))
+	api.Spec.NodeName = "worker-1"
+	worker := testPod("batch", "worker", testContainer("app", "1", "4Gi"))
+	worker.Spec.NodeName = "worker-1"
+	unscheduled := testPod("web", "pending", testContainer("app", "1", "1Gi"))
+
+	report := calc.Calculate(Inventory{
+		Pods:  []corev1.Pod{api, worker, unscheduled},
+		Nodes: []corev1.Node{node},
+	})
+
+	// $75 cpu pool, $25 memory pool
+	wantAPI := 75*0.5 + 25*0.25
+	wantWorker := 75*0.25 + 25*0.5
+	wantIdle := 75*0.25 + 25*0.25
+	costs := map[string]float64{}
+	for _, p := range report.Pods {
+		costs[p.Name] = p.Cost
+	}
+	if !approxEqual(costs["api"], wantAPI) || !approxEqual(costs["worker"], wantWorker) || costs["pending"] != 0 {
+		t.Fatalf("unexpected pod costs %+v", costs)
+	}
+	if !approxEqual(report.IdleCost, wantIdle) {
+		t.Fatalf("expected idle %.4f, got %.4f", wantIdle, report.IdleCost)
+	}
+
+	var nsTotal float64
+	var sawIdle bool
+	for _, ns := range report.Namespaces {
+		nsTotal += ns.Cost
+		sawIdle = sawIdle || ns.Name == IdleNamespace
+	}
+	if !sawIdle {
+		t.Fatalf("expected idle namespace bucket, got %+v", report.Namespaces)
+	}
+	if nodeTotal := report.HardwareCost + report.ElecCost; !approxEqual(nsTotal, nodeTotal) {
+		t.Fatalf("expected namespaces to reconcile to node cost %.4f, got %.4f", nodeTotal, nsTotal)
+	}
+}
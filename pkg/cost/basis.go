@@ -0,0 +1,78 @@
+package cost
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/newman-bot/kfin/pkg/stats"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// CostBasis selects which pod quantity is multiplied by the usage rates.
+type CostBasis string
+
+const (
+	BasisRequests        CostBasis = "requests"
+	BasisLimits          CostBasis = "limits"
+	BasisUsage           CostBasis = "usage"
+	BasisMaxRequestUsage CostBasis = "max-request-usage"
+)
+
+// ParseCostBasis validates a cost basis name. An empty string means requests.
+func ParseCostBasis(s string) (CostBasis, error) {
+	switch b := CostBasis(strings.ToLower(strings.TrimSpace(s))); b {
+	case "":
+		return BasisRequests, nil
+	case BasisRequests, BasisLimits, BasisUsage, BasisMaxRequestUsage:
+		return b, nil
+	default:
+		return "", fmt.Errorf("invalid cost basis %q (expected: requests, limits, usage or max-request-usage)", s)
+	}
+}
+
+// NeedsUsage reports whether the basis requires per-pod usage from Prometheus.
+func (b CostBasis) NeedsUsage() bool {
+	return b == BasisUsage || b == BasisMaxRequestUsage
+}
+
+// limitsOrRequests returns the container's limits, falling back to its
+// request for any resource without a limit.
+func limitsOrRequests(c corev1.Container) corev1.ResourceList {
+	out := corev1.ResourceList{}
+	addResources(out, c.Resources.Requests)
+	for name, q := range c.Resources.Limits {
+		out[name] = q.DeepCopy()
+	}
+	return out
+}
+
+// usageResources converts a pod's average usage into billable quantities.
+func usageResources(u stats.Usage) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewMilliQuantity(int64(u.CPU.Avg*1000), resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(int64(u.Memory.Avg), resource.BinarySI),
+	}
+}
+
+// billedResources returns the quantities pod is charged for under the
+// calculator's basis and the basis actually applied. Usage-based modes fall
+// back to requests for pods without usage samples.
+func (c *Calculator) billedResources(pod corev1.Pod, requests PodRequests, usage *stats.Usage) (corev1.ResourceList, CostBasis) {
+	switch c.basis {
+	case BasisLimits:
+		return podResources(pod, limitsOrRequests).Effective, BasisLimits
+	case BasisUsage:
+		if usage != nil {
+			return usageResources(*usage), BasisUsage
+		}
+	case BasisMaxRequestUsage:
+		if usage != nil {
+			billed := corev1.ResourceList{}
+			addResources(billed, requests.Effective)
+			maxResources(billed, usageResources(*usage))
+			return billed, BasisMaxRequestUsage
+		}
+	}
+	return requests.Effective, BasisRequests
+}
@@ -0,0 +1,75 @@
+package cost
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/pricing"
+	"github.com/newman-bot/kfin/pkg/stats"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestParseCostBasis(t *testing.T) {
+	if b, err := ParseCostBasis(""); err != nil || b != BasisRequests {
+		t.Fatalf("expected empty basis to mean requests, got %q (%v)", b, err)
+	}
+	if b, err := ParseCostBasis(" Max-Request-Usage "); err != nil || b != BasisMaxRequestUsage {
+		t.Fatalf("expected max-request-usage, got %q (%v)", b, err)
+	}
+	if _, err := ParseCostBasis("peak"); err == nil {
+		t.Fatalf("expected error for invalid basis")
+	}
+}
+
+func TestCalculate_CostBasis(t *testing.T) {
+	limited := testContainer("app", "100m", "128Mi")
+	limited.Resources.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+	pods := []corev1.Pod{
+		testPod("ns", "limited", limited),
+		testPod("ns", "besteffort", testContainer("app", "", "")),
+		testPod("ns", "unsampled", testContainer("app", "200m", "")),
+	}
+	usage := map[stats.PodKey]stats.Usage{
+		{Namespace: "ns", Name: "limited"}: {
+			CPU:    stats.Aggregate{Avg: 0.05},
+			Memory: stats.Aggregate{Avg: 256 * 1024 * 1024},
+		},
+		{Namespace: "ns", Name: "besteffort"}: {CPU: stats.Aggregate{Avg: 2}},
+	}
+
+	tests := []struct {
+		basis   CostBasis
+		wantCPU map[string]string
+		missing int
+	}{
+		{BasisRequests, map[string]string{"limited": "100m", "besteffort": "0", "unsampled": "200m"}, 0},
+		{BasisLimits, map[string]string{"limited": "1", "besteffort": "0", "unsampled": "200m"}, 0},
+		{BasisUsage, map[string]string{"limited": "50m", "besteffort": "2", "unsampled": "200m"}, 1},
+		{BasisMaxRequestUsage, map[string]string{"limited": "100m", "besteffort": "2", "unsampled": "200m"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.basis), func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Pricing.CostBasis = string(tt.basis)
+			calc, err := NewCalculator(context.Background(), cfg, pricing.NewStaticProvider(0.02, 0.005))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			report := calc.Calculate(Inventory{Pods: pods, Usage: usage})
+
+			if report.UsageMissing != tt.missing {
+				t.Fatalf("expected %d pods without usage, got %d", tt.missing, report.UsageMissing)
+			}
+			for _, p := range report.Pods {
+				want := resource.MustParse(tt.wantCPU[p.Name])
+				if p.CPU.Cmp(want) != 0 {
+					t.Fatalf("%s: expected cpu %s, got %s", p.Name, want.String(), p.CPU.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package cost
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/newman-bot/kfin/pkg/config"
+)
+
+// Budget scopes.
+const (
+	BudgetCluster   = "cluster"
+	BudgetNamespace = "namespace"
+	BudgetLabel     = "label"
+)
+
+// Where a namespace budget came from.
+const (
+	BudgetSourceConfig     = "config"
+	BudgetSourceAnnotation = "annotation"
+)
+
+// BudgetStatus grades a budget's burn against the warn and critical
+// thresholds.
+type BudgetStatus int
+
+const (
+	BudgetOK BudgetStatus = iota
+	BudgetWarn
+	BudgetCritical
+)
+
+func (s BudgetStatus) String() string {
+	switch s {
+	case BudgetCritical:
+		return "critical"
+	case BudgetWarn:
+		return "warn"
+	default:
+		return "ok"
+	}
+}
+
+// BudgetResult is one budget evaluated against the report. Name is the
+// namespace, "key=value" for label budgets, or the cluster scope. Burn is
+// Cost over Limit, so 1 is exactly on budget.
+type BudgetResult struct {
+	Scope  string
+	Name   string
+	Source string
+	Limit  float64
+	Cost   float64
+	Burn   float64
+	Status BudgetStatus
+}
+
+// budgetPolicy is a validated config.BudgetsConfig.
+type budgetPolicy struct {
+	warn, critical float64
+	cluster        float64
+	namespaces     map[string]float64
+	labels         map[string]map[string]float64
+}
+
+func parseBudgets(cfg config.BudgetsConfig) (budgetPolicy, error) {
+	if cfg.Warn <= 0 || cfg.Critical <= 0 || cfg.Warn > cfg.Critical {
+		return budgetPolicy{}, fmt.Errorf("budgets: need 0 < warn (%g) <= critical (%g)", cfg.Warn, cfg.Critical)
+	}
+	check := func(field string, v float64) error {
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("budgets.%s must be a non-negative number, got %g", field, v)
+		}
+		return nil
+	}
+	if err := check("cluster", cfg.Cluster); err != nil {
+		return budgetPolicy{}, err
+	}
+	for ns, v := range cfg.Namespaces {
+		if err := check("namespaces."+ns, v); err != nil {
+			return budgetPolicy{}, err
+		}
+	}
+	for key, values := range cfg.Labels {
+		for value, v := range values {
+			if err := check("labels."+key+"."+value, v); err != nil {
+				return budgetPolicy{}, err
+			}
+		}
+	}
+	return budgetPolicy{
+		warn:       cfg.Warn,
+		critical:   cfg.Critical,
+		cluster:    cfg.Cluster,
+		namespaces: cfg.Namespaces,
+		labels:     cfg.Labels,
+	}, nil
+}
+
+// applyBudgets sets configured namespace budgets on the rollup, replacing
+// annotation budgets, and evaluates every budget: the cluster's against
+// TotalCost, namespaces' against their rollup cost, and label budgets
+// against the label:<key> groups. Results are ordered by burn, highest
+// first.
+func (p budgetPolicy) applyBudgets(report *Report) []BudgetResult {
+	var out []BudgetResult
+	add := func(scope, name, source string, limit, cost float64) {
+		if limit <= 0 {
+			return
+		}
+		burn := cost / limit
+		out = append(out, BudgetResult{
+			Scope:  scope,
+			Name:   name,
+			Source: source,
+			Limit:  limit,
+			Cost:   cost,
+			Burn:   burn,
+			Status: p.status(burn),
+		})
+	}
+
+	add(BudgetCluster, BudgetCluster, BudgetSourceConfig, p.cluster, report.TotalCost)
+
+	seen := make(map[string]bool, len(report.Namespaces))
+	for i := range report.Namespaces {
+		ns := &report.Namespaces[i]
+		seen[ns.Name] = true
+		source := BudgetSourceAnnotation
+		if limit, ok := p.namespaces[ns.Name]; ok {
+			ns.Budget, source = limit, BudgetSourceConfig
+		}
+		add(BudgetNamespace, ns.Name, source, ns.Budget, ns.Cost)
+	}
+	// Budgeted namespaces with no cost this month are on budget, but still
+	// listed so a typo in the config shows up as a 0% row.
+	for ns, limit := range p.namespaces {
+		if !seen[ns] {
+			add(BudgetNamespace, ns, BudgetSourceConfig, limit, 0)
+		}
+	}
+
+	for key, values := range p.labels {
+		costs := make(map[string]float64)
+		for _, g := range GroupCosts(report, []GroupDimension{{Kind: GroupLabel, Label: key}}) {
+			costs[g.Keys[0]] += g.Cost
+		}
+		for value, limit := range values {
+			add(BudgetLabel, key+"="+value, BudgetSourceConfig, limit, costs[value])
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Burn != out[j].Burn {
+			return out[i].Burn > out[j].Burn
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+func (p budgetPolicy) status(burn float64) BudgetStatus {
+	switch {
+	case burn >= p.critical:
+		return BudgetCritical
+	case burn >= p.warn:
+		return BudgetWarn
+	default:
+		return BudgetOK
+	}
+}
+
+// NamespaceBudget returns the evaluated budget of a namespace, if it has one.
+func (r *Report) NamespaceBudget(name string) (BudgetResult, bool) {
+	for _, b := range r.Budgets {
+		if b.Scope == BudgetNamespace && b.Name == name {
+			return b, true
+		}
+	}
+	return BudgetResult{}, false
+}
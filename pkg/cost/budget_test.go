@@ -0,0 +1,91 @@
+package cost
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBudgets(t *testing.T) {
+	shop := testPod("shop", "web", testContainer("app", "1", "1Gi"))
+	shop.Labels = map[string]string{"team": "payments"}
+	search := testPod("search", "api", testContainer("app", "2", "2Gi"))
+	inv := Inventory{Pods: []corev1.Pod{shop, search}}
+
+	costs := make(map[string]float64)
+	for _, ns := range newTestCalculator(t, nil).Calculate(inv).Namespaces {
+		costs[ns.Name] = ns.Cost
+	}
+
+	// The annotation puts search at 83% of budget, a warning; the config
+	// overrides shop's annotated budget and puts it over.
+	inv.Namespaces = []corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "shop", Annotations: map[string]string{AnnotationBudget: "100000"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "search", Annotations: map[string]string{AnnotationBudget: fmt.Sprintf("%.4f", costs["search"]*1.2)}}},
+	}
+	cfg := config.DefaultConfig()
+	cfg.Budgets.Namespaces = map[string]float64{"shop": costs["shop"] * 0.9, "retired": 50}
+	cfg.Budgets.Labels = map[string]map[string]float64{"team": {"payments": costs["shop"] * 10}}
+	report := newTestCalculator(t, cfg).Calculate(inv)
+
+	want := []struct {
+		scope, name, source string
+		status              BudgetStatus
+	}{
+		{BudgetNamespace, "shop", BudgetSourceConfig, BudgetCritical},
+		{BudgetNamespace, "search", BudgetSourceAnnotation, BudgetWarn},
+		{BudgetLabel, "team=payments", BudgetSourceConfig, BudgetOK},
+		{BudgetNamespace, "retired", BudgetSourceConfig, BudgetOK},
+	}
+	if len(report.Budgets) != len(want) {
+		t.Fatalf("expected %d budgets, got %+v", len(want), report.Budgets)
+	}
+	for i, w := range want {
+		b := report.Budgets[i]
+		if b.Scope != w.scope || b.Name != w.name || b.Source != w.source || b.Status != w.status {
+			t.Fatalf("budget %d: expected %s %s from %s at %s, got %+v", i, w.scope, w.name, w.source, w.status, b)
+		}
+	}
+
+	b, ok := report.NamespaceBudget("shop")
+	if !ok || !approxEqual(b.Burn, 1/0.9) || !approxEqual(b.Cost, costs["shop"]) {
+		t.Fatalf("expected shop to burn %.3f of its budget, got %+v", 1/0.9, b)
+	}
+	for _, ns := range report.Namespaces {
+		if ns.Name == "shop" && !approxEqual(ns.Budget, costs["shop"]*0.9) {
+			t.Fatalf("expected the configured budget on the rollup, got %v", ns.Budget)
+		}
+	}
+}
+
+func TestBudgets_Cluster(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Budgets.Cluster = 1
+	report := newTestCalculator(t, cfg).Calculate(Inventory{Nodes: []corev1.Node{testNode("pi-1", "4Gi", nil)}})
+	if report.TotalCost <= 1 {
+		t.Fatalf("expected the node to cost more than $1, got %v", report.TotalCost)
+	}
+	if len(report.Budgets) != 1 || report.Budgets[0].Scope != BudgetCluster || report.Budgets[0].Status != BudgetCritical {
+		t.Fatalf("expected a breached cluster budget, got %+v", report.Budgets)
+	}
+}
+
+func TestParseBudgets_Invalid(t *testing.T) {
+	cases := map[string]func(*config.BudgetsConfig){
+		"warn above critical": func(b *config.BudgetsConfig) { b.Warn, b.Critical = 1.2, 1 },
+		"zero warn":           func(b *config.BudgetsConfig) { b.Warn = 0 },
+		"negative cluster":    func(b *config.BudgetsConfig) { b.Cluster = -1 },
+		"negative namespace":  func(b *config.BudgetsConfig) { b.Namespaces = map[string]float64{"shop": -5} },
+		"negative label":      func(b *config.BudgetsConfig) { b.Labels = map[string]map[string]float64{"team": {"a": -1}} },
+	}
+	for name, mutate := range cases {
+		budgets := config.DefaultConfig().Budgets
+		mutate(&budgets)
+		if _, err := parseBudgets(budgets); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/pricing"
+	"github.com/newman-bot/kfin/pkg/stats"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// HoursPerMonth is the average number of hours in a month used for all
+// hourly-to-monthly conversions.
+const HoursPerMonth = 730
+
+const nodeInstanceTypeLabel = "node.kubernetes.io/instance-type"
+
+// BytesPerGB converts memory bytes to the GiB that per-GB rates are quoted in.
+const BytesPerGB = 1024 * 1024 * 1024
+
+// Inventory is the set of cluster objects a Calculator prices. Only Pods and
+// Nodes are required; ReplicaSets and Jobs let pods roll up to Deployments
+// and CronJobs, claims and volumes add storage cost, Services and Ingresses
+// add load balancer cost, and Namespaces supply labels for grouping and
+// ownership annotations. Usage feeds the usage-based cost bases,
+// ContainerUsage lets findings flag over-requested containers,
+// NetworkTransmit (bytes per second by pod) adds network cost, and NodePower
+// feeds the power model, keyed by node name. CollectedAt anchors runtime
+// proration to a billing month and defaults to the current time.
+type Inventory struct {
+	Pods                   []corev1.Pod
+	Nodes                  []corev1.Node
+	ReplicaSets            []appsv1.ReplicaSet
+	Jobs                   []batchv1.Job
+	StatefulSets           []appsv1.StatefulSet
+	DaemonSets             []appsv1.DaemonSet
+	PersistentVolumeClaims []corev1.PersistentVolumeClaim
+	PersistentVolumes      []corev1.PersistentVolume
+	Services               []corev1.Service
+	Ingresses              []networkingv1.Ingress
+	Namespaces             []corev1.Namespace
+	Usage                  map[stats.PodKey]stats.Usage
+	ContainerUsage         map[stats.UsageKey]stats.Usage
+	NetworkTransmit        map[stats.PodKey]stats.Aggregate
+	NodePower              map[string]NodePower
+	CollectedAt            time.Time
+}
+
+// Calculator turns pods and nodes into a cost Report using a config and
+// usage rates resolved from a pricing.Provider. Extended resource rates the
+// provider does not set come from pricing.cloud.extended_per_hour.
+type Calculator struct {
+	cfg            *config.Config
+	rates          pricing.UsageRates
+	source         string
+	basis          CostBasis
+	allocation     AllocationMode
+	cpuMemoryRatio float64
+
+	includeTerminated bool
+	prorate           bool
+	project           bool
+	profiles          []hardwareProfile
+	powerProfiles     []powerProfile
+	chargebackPolicy  *chargebackPolicy
+	rightsizeOptions  RightsizeOptions
+	findingsOptions   findingsOptions
+	budgets           budgetPolicy
+}
+
+// NewCalculator resolves usage rates from provider once and returns a
+// Calculator bound to them. A nil cfg falls back to config.DefaultConfig.
+func NewCalculator(ctx context.Context, cfg *config.Config, provider pricing.Provider) (*Calculator, error) {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("pricing provider is nil")
+	}
+	basis, err := ParseCostBasis(cfg.Pricing.CostBasis)
+	if err != nil {
+		return nil, err
+	}
+	allocation, err := ParseAllocationMode(cfg.Pricing.Allocation.Mode)
+	if err != nil {
+		return nil, err
+	}
+	ratio := cfg.Pricing.Allocation.CPUMemoryRatio
+	if ratio < 0 {
+		return nil, fmt.Errorf("pricing.allocation.cpu_memory_ratio must not be negative")
+	}
+	if ratio == 0 {
+		ratio = 1
+	}
+
+	profiles, err := parseHardwareProfiles(cfg.Pricing.HardwareProfiles)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSpotDiscount(cfg.Pricing.SpotDiscount); err != nil {
+		return nil, err
+	}
+	if cfg.Pricing.ProjectRuntime && !cfg.Pricing.ProrateRuntime {
+		return nil, fmt.Errorf("pricing.project_runtime requires pricing.prorate_runtime")
+	}
+	if err := validatePowerSource(cfg.Pricing.Power.Source); err != nil {
+		return nil, err
+	}
+	if err := validatePUE(cfg.Pricing.Power.PUE); err != nil {
+		return nil, err
+	}
+	powerProfiles, err := parsePowerProfiles(cfg.Pricing.Power.Profiles)
+	if err != nil {
+		return nil, err
+	}
+	chargeback, err := parseChargeback(cfg.Pricing.Chargeback)
+	if err != nil {
+		return nil, err
+	}
+	rightsize, err := ParseRightsizeOptions(cfg.Recommend)
+	if err != nil {
+		return nil, err
+	}
+	findings, err := parseFindings(cfg.Findings)
+	if err != nil {
+		return nil, err
+	}
+	budgets, err := parseBudgets(cfg.Budgets)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := provider.UsageRates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s pricing rates: %w", provider.Source(), err)
+	}
+	rates.Extended = mergeExtendedRates(rates.Extended, cfg.Pricing.Cloud.ExtendedPerHour)
+
+	return &Calculator{
+		cfg:            cfg,
+		rates:          rates,
+		source:         provider.Source(),
+		basis:          basis,
+		allocation:     allocation,
+		cpuMemoryRatio: ratio,
+
+		includeTerminated: cfg.Pricing.IncludeTerminated,
+		prorate:           cfg.Pricing.ProrateRuntime,
+		project:           cfg.Pricing.ProjectRuntime,
+		profiles:          profiles,
+		powerProfiles:     powerProfiles,
+		chargebackPolicy:  chargeback,
+		rightsizeOptions:  rightsize,
+		findingsOptions:   findings,
+		budgets:           budgets,
+	}, nil
+}
+
+// Rates returns the usage rates the calculator prices requests with.
+func (c *Calculator) Rates() pricing.UsageRates {
+	return c.rates
+}
+
+// Source returns the name of the pricing source the rates came from.
+func (c *Calculator) Source() string {
+	return c.source
+}
+
+// Basis returns the configured cost basis.
+func (c *Calculator) Basis() CostBasis {
+	return c.basis
+}
+
+// Allocation returns the configured allocation mode.
+func (c *Calculator) Allocation() AllocationMode {
+	return c.allocation
+}
+
+// Calculate prices every pod and node in inv and returns the combined report.
+func (c *Calculator) Calculate(inv Inventory) *Report {
+	now := inv.CollectedAt
+	if now.IsZero() {
+		now = time.Now()
+	}
+	report := &Report{
+		PricingSource:  c.source,
+		Rates:          c.rates,
+		CostBasis:      c.basis,
+		Allocation:     c.allocation,
+		CPUMemoryRatio: c.cpuMemoryRatio,
+		Prorated:       c.prorate,
+		Projected:      c.project,
+		Period:         MonthPeriod(now),
+		Distribution:   DetectDistribution(inv.Nodes),
+	}
+
+	owners := newOwnerIndex(inv.ReplicaSets, inv.Jobs)
+	fargate := fargateNodeNames(inv.Nodes)
+	for _, pod := range inv.Pods {
+		if !c.includeTerminated && isTerminated(pod.Status.Phase) {
+			report.TerminatedExcluded++
+			continue
+		}
+		var usage *stats.Usage
+		if u, ok := inv.Usage[stats.PodKey{Namespace: pod.Namespace, Name: pod.Name}]; ok {
+			usage = &u
+		}
+		pc := c.PodCost(pod, usage)
+		pc.Workload = owners.resolve(pod)
+		if fargate[pc.NodeName] {
+			c.priceFargatePod(&pc)
+		}
+		if c.prorate {
+			pc.RuntimeFraction = runtimeFraction(pod, report.Period, now, c.project)
+			pc.Cost *= pc.RuntimeFraction
+			pc.ExtendedCost *= pc.RuntimeFraction
+		}
+		if pc.Fargate {
+			report.FargateCost += pc.Cost
+			report.FargatePods++
+		} else if c.basis.NeedsUsage() && usage == nil {
+			report.UsageMissing++
+		}
+		report.RequestedCPU.Add(pc.CPU)
+		report.RequestedMemory.Add(pc.Memory)
+		report.Pods = append(report.Pods, pc)
+	}
+
+	for _, node := range inv.Nodes {
+		report.Nodes = append(report.Nodes, c.NodeCost(node, inv.NodePower))
+	}
+
+	report.HardwareCost, report.ElecCost, report.ControlPlaneCost = c.ClusterCosts(inv.Nodes, inv.NodePower)
+	summarizeSpot(report)
+	c.allocateNodeCosts(report)
+	c.priceVolumes(report, inv.PersistentVolumeClaims, inv.PersistentVolumes)
+	c.priceLoadBalancers(report, inv.Services, inv.Ingresses, IsEKSCluster(inv.Nodes))
+	c.priceNetwork(report, inv.Nodes, inv.NetworkTransmit)
+	report.TotalCost = report.HardwareCost + report.ElecCost + report.ControlPlaneCost +
+		report.StorageCost + report.LoadBalancerCost + report.NetworkCost + report.FargateCost
+
+	for _, pc := range report.Pods {
+		report.PodTotalCost += pc.Cost
+	}
+	report.ExtendedResources = extendedNames(report.Pods)
+	report.Workloads = summarizeWorkloads(report.Pods)
+	report.Namespaces = summarizeNamespaces(report)
+	report.NamespaceLabels = namespaceLabels(inv.Namespaces)
+	annotateNamespaces(report.Namespaces, inv.Namespaces)
+	report.NamespaceOwnership = namespaceOwnership(report.Namespaces, inv.Namespaces)
+	report.Budgets = c.budgets.applyBudgets(report)
+	report.Chargeback = c.chargeback(report)
+	report.Findings = c.findings(inv, report, now)
+	return report
+}
+
+// PodCost prices the pod under the calculator's cost basis. Extended resources
+// with a rate are always billed on requests, since Prometheus has no usage for
+// them. Requests always holds the pod's effective request: app containers plus
+// native sidecars, or the init phase peak if larger, plus pod overhead. usage
+// may be nil when no samples exist. Workload is left unresolved and
+// RuntimeFraction is 1; Calculate fills them from the inventory's owners and,
+// when prorating, the pod's runtime in the billing month.
+func (c *Calculator) PodCost(pod corev1.Pod, usage *stats.Usage) PodCost {
+	pc := PodCost{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		NodeName:  pod.Spec.NodeName,
+		Phase:     pod.Status.Phase,
+		Labels:    pod.Labels,
+		Requests:  podRequests(pod),
+		Claims:    podClaims(pod),
+
+		RuntimeFraction: 1,
+	}
+	for _, container := range pod.Spec.InitContainers {
+		kind := ContainerKindInit
+		if isSidecar(container) {
+			kind = ContainerKindSidecar
+		}
+		pc.Containers = append(pc.Containers, c.containerCost(container, kind))
+	}
+	for _, container := range pod.Spec.Containers {
+		pc.Containers = append(pc.Containers, c.containerCost(container, ContainerKindApp))
+	}
+
+	billed, basis := c.billedResources(pod, pc.Requests, usage)
+	pc.Basis = basis
+	pc.CPU = billed.Cpu().DeepCopy()
+	pc.Memory = billed.Memory().DeepCopy()
+	pc.Extended = c.extendedResources(pc.Requests.Effective)
+	pc.ExtendedCost = c.ExtendedCost(pc.Extended)
+	pc.Cost = c.ContainerCost(&pc.CPU, &pc.Memory) + pc.ExtendedCost
+	return pc
+}
+
+func (c *Calculator) containerCost(container corev1.Container, kind string) ContainerCost {
+	cpu := container.Resources.Requests.Cpu()
+	mem := container.Resources.Requests.Memory()
+	return ContainerCost{
+		Name:   container.Name,
+		Kind:   kind,
+		CPU:    cpu.DeepCopy(),
+		Memory: mem.DeepCopy(),
+		Cost:   c.ContainerCost(cpu, mem),
+	}
+}
+
+// ContainerCost returns the monthly cost of the given CPU and memory at the
+// calculator's usage rates.
+func (c *Calculator) ContainerCost(cpu *resource.Quantity, mem *resource.Quantity) float64 {
+	cpuCores := float64(cpu.MilliValue()) / 1000.0
+	memGB := float64(mem.Value()) / BytesPerGB
+
+	monthlyCPUCost := cpuCores * HoursPerMonth * c.rates.CPUPerHour
+	monthlyMemCost := memGB * HoursPerMonth * c.rates.MemPerGBHour
+
+	return monthlyCPUCost + monthlyMemCost
+}
+
+// NodeCost returns the monthly hardware and electricity cost of node. power
+// may be nil; see NodeWatts. Fargate nodes cost nothing themselves; their
+// pods are billed directly.
+func (c *Calculator) NodeCost(node corev1.Node, power map[string]NodePower) NodeCost {
+	if IsFargateNode(node) {
+		allocatable := nodeAllocatable(node)
+		return NodeCost{
+			Name:              node.Name,
+			CapacityType:      CapacityFargate,
+			Zone:              node.Labels[zoneLabel],
+			MemoryGB:          float64(node.Status.Capacity.Memory().Value()) / BytesPerGB,
+			AllocatableCPU:    allocatable.Cpu().DeepCopy(),
+			AllocatableMemory: allocatable.Memory().DeepCopy(),
+		}
+	}
+	hardwareCost, instanceType, override := c.NodeHardwareCost(node)
+	watts, powerSource := c.NodeWatts(node, power)
+	elecCost := c.NodeElectricityCost(node, power)
+	allocatable := nodeAllocatable(node)
+	var profile string
+	if p := c.hardwareProfile(node); p != nil {
+		profile = p.Name
+	}
+	return NodeCost{
+		Name:              node.Name,
+		InstanceType:      instanceType,
+		InstanceOverride:  override,
+		HardwareProfile:   profile,
+		CapacityType:      NodeCapacityType(node),
+		Zone:              node.Labels[zoneLabel],
+		OnDemandCost:      c.NodeOnDemandCost(node),
+		MemoryGB:          float64(node.Status.Capacity.Memory().Value()) / BytesPerGB,
+		HardwareCost:      hardwareCost,
+		ElecCost:          elecCost,
+		TotalCost:         hardwareCost + elecCost,
+		Watts:             watts,
+		PowerSource:       powerSource,
+		AllocatableCPU:    allocatable.Cpu().DeepCopy(),
+		AllocatableMemory: allocatable.Memory().DeepCopy(),
+	}
+}
+
+// NodeHardwareCost returns the node's monthly hardware cost, its instance type
+// label and whether the cost came from the instance type tables. The first
+// matching pricing.hardware_profiles entry wins over the instance type tables,
+// which price spot nodes by capacity type and zone; nodes matching neither are
+// priced by memory and CPU capacity.
+func (c *Calculator) NodeHardwareCost(node corev1.Node) (float64, string, bool) {
+	instanceType := node.Labels[nodeInstanceTypeLabel]
+	if profile := c.hardwareProfile(node); profile != nil {
+		return profile.monthly(node), instanceType, false
+	}
+	if instanceType != "" {
+		if monthly, _, ok := c.instancePrice(instanceType, NodeCapacityType(node), node.Labels[zoneLabel]); ok {
+			return monthly, instanceType, true
+		}
+	}
+
+	return capacityCost(node, c.cfg.Pricing.HardwareMonthlyPerGB, c.cfg.Pricing.HardwareMonthlyPerCore), instanceType, false
+}
+
+// NodeElectricityCost returns the monthly electricity cost of node: its draw
+// from NodeWatts, scaled by pricing.power.pue.
+func (c *Calculator) NodeElectricityCost(node corev1.Node, power map[string]NodePower) float64 {
+	watts, _ := c.NodeWatts(node, power)
+	return watts * c.pue() / 1000.0 * HoursPerMonth * c.cfg.Pricing.ElectricityRate
+}
+
+// ClusterCosts returns the monthly hardware, electricity and control plane
+// cost of a cluster made up of nodes. The control plane is priced for the
+// distribution DetectDistribution finds. Fargate nodes are left out; their pods
+// are priced individually.
+func (c *Calculator) ClusterCosts(nodes []corev1.Node, power map[string]NodePower) (float64, float64, float64) {
+	var hardwareCost, elecCost float64
+	for _, node := range nodes {
+		if IsFargateNode(node) {
+			continue
+		}
+		nodeHardware, _, _ := c.NodeHardwareCost(node)
+		hardwareCost += nodeHardware
+		elecCost += c.NodeElectricityCost(node, power)
+	}
+
+	controlPlaneCost := HoursPerMonth * c.ControlPlanePerHour(DetectDistribution(nodes))
+
+	return hardwareCost, elecCost, controlPlaneCost
+}
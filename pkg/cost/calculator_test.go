@@ -0,0 +1,127 @@
+package cost
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/pricing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestCalculator(t *testing.T, cfg *config.Config) *Calculator {
+	t.Helper()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	calc, err := NewCalculator(context.Background(), cfg, pricing.NewStaticProvider(0.02, 0.005))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return calc
+}
+
+func testPod(namespace, name string, containers ...corev1.Container) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       corev1.PodSpec{Containers: containers},
+	}
+}
+
+func testContainer(name, cpu, mem string) corev1.Container {
+	requests := corev1.ResourceList{}
+	if cpu != "" {
+		requests[corev1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if mem != "" {
+		requests[corev1.ResourceMemory] = resource.MustParse(mem)
+	}
+	return corev1.Container{Name: name, Resources: corev1.ResourceRequirements{Requests: requests}}
+}
+
+func testNode(name, mem string, labels map[string]string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(mem)},
+		},
+	}
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestNewCalculator_NilProvider(t *testing.T) {
+	if _, err := NewCalculator(context.Background(), nil, nil); err == nil {
+		t.Fatalf("expected error for nil provider")
+	}
+}
+
+func TestCalculate_PodCosts(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+	pods := []corev1.Pod{
+		testPod("web", "nginx-1", testContainer("nginx", "500m", "1Gi"), testContainer("sidecar", "", "")),
+		testPod("batch", "job-1", testContainer("worker", "2", "")),
+	}
+
+	report := calc.Calculate(Inventory{Pods: pods})
+
+	if len(report.Pods) != 2 {
+		t.Fatalf("expected 2 pods, got %d", len(report.Pods))
+	}
+	nginx := report.Pods[0]
+	want := 0.5*HoursPerMonth*0.02 + 1*HoursPerMonth*0.005
+	if !approxEqual(nginx.Cost, want) {
+		t.Fatalf("expected nginx cost %.6f, got %.6f", want, nginx.Cost)
+	}
+	if len(nginx.Containers) != 2 || nginx.Containers[1].Cost != 0 {
+		t.Fatalf("expected zero-cost sidecar container, got %+v", nginx.Containers)
+	}
+
+	wantTotal := want + 2*HoursPerMonth*0.02
+	if !approxEqual(report.PodTotalCost, wantTotal) {
+		t.Fatalf("expected pod total %.6f, got %.6f", wantTotal, report.PodTotalCost)
+	}
+	if got := report.RequestedCPU.MilliValue(); got != 2500 {
+		t.Fatalf("expected 2500m requested cpu, got %dm", got)
+	}
+	if report.PricingSource != "config" {
+		t.Fatalf("expected pricing source config, got %s", report.PricingSource)
+	}
+}
+
+func TestCalculate_ClusterCosts(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.InstanceMonthlyByType = map[string]float64{"m5.large": 70}
+	calc := newTestCalculator(t, cfg)
+
+	nodes := []corev1.Node{
+		testNode("pi-1", "4Gi", nil),
+		testNode("ec2-1", "8Gi", map[string]string{
+			nodeInstanceTypeLabel:         "m5.large",
+			"eks.amazonaws.com/nodegroup": "default",
+		}),
+	}
+
+	report := calc.Calculate(Inventory{Nodes: nodes})
+
+	wantHardware := 4*cfg.Pricing.HardwareMonthlyPerGB + 70
+	if !approxEqual(report.HardwareCost, wantHardware) {
+		t.Fatalf("expected hardware %.6f, got %.6f", wantHardware, report.HardwareCost)
+	}
+	if !report.Nodes[1].InstanceOverride || report.Nodes[1].InstanceType != "m5.large" {
+		t.Fatalf("expected instance override for ec2-1, got %+v", report.Nodes[1])
+	}
+	wantControlPlane := HoursPerMonth * cfg.Pricing.EKS.ControlPlanePerHour
+	if !approxEqual(report.ControlPlaneCost, wantControlPlane) {
+		t.Fatalf("expected control plane %.6f, got %.6f", wantControlPlane, report.ControlPlaneCost)
+	}
+	wantTotal := report.HardwareCost + report.ElecCost + report.ControlPlaneCost
+	if !approxEqual(report.TotalCost, wantTotal) {
+		t.Fatalf("expected total %.6f, got %.6f", wantTotal, report.TotalCost)
+	}
+}
@@ -0,0 +1,98 @@
+package cost
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Capacity types a node can be provisioned with.
+const (
+	CapacityOnDemand = "on-demand"
+	CapacitySpot     = "spot"
+)
+
+// Labels set by Karpenter and EKS managed node groups; the former uses
+// spot/on-demand, the latter SPOT/ON_DEMAND.
+const (
+	karpenterCapacityTypeLabel = "karpenter.sh/capacity-type"
+	eksCapacityTypeLabel       = "eks.amazonaws.com/capacityType"
+)
+
+// NodeCapacityType returns CapacitySpot or CapacityOnDemand from the node's
+// capacity type label, or "" when it carries none.
+func NodeCapacityType(node corev1.Node) string {
+	for _, label := range []string{karpenterCapacityTypeLabel, eksCapacityTypeLabel} {
+		v, ok := node.Labels[label]
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.ReplaceAll(v, "_", "-")) {
+		case CapacitySpot:
+			return CapacitySpot
+		case CapacityOnDemand:
+			return CapacityOnDemand
+		}
+	}
+	return ""
+}
+
+func validateSpotDiscount(discount float64) error {
+	if discount < 0 || discount >= 1 {
+		return fmt.Errorf("pricing.spot_discount must be at least 0 and below 1, got %g", discount)
+	}
+	return nil
+}
+
+// instancePrice returns the monthly price of an instance type under
+// capacityType in zone, and its on-demand price. Spot nodes are priced from
+// pricing.spot_monthly_by_zone, then pricing.spot_monthly_by_type, then the
+// on-demand price less pricing.spot_discount. ok is false when neither table
+// prices the type.
+func (c *Calculator) instancePrice(instanceType, capacityType, zone string) (monthly, onDemand float64, ok bool) {
+	onDemand = c.cfg.Pricing.InstanceMonthlyByType[instanceType]
+	if capacityType != CapacitySpot {
+		return onDemand, onDemand, onDemand > 0
+	}
+	if spot := c.cfg.Pricing.SpotMonthlyByZone[zone][instanceType]; zone != "" && spot > 0 {
+		return spot, onDemand, true
+	}
+	if spot := c.cfg.Pricing.SpotMonthlyByType[instanceType]; spot > 0 {
+		return spot, onDemand, true
+	}
+	return onDemand * (1 - c.cfg.Pricing.SpotDiscount), onDemand, onDemand > 0
+}
+
+// NodeOnDemandCost returns what the node's hardware would cost on on-demand
+// capacity. It equals NodeHardwareCost unless the node is spot capacity with
+// an on-demand price in pricing.instance_monthly_by_type.
+func (c *Calculator) NodeOnDemandCost(node corev1.Node) float64 {
+	hardwareCost, instanceType, override := c.NodeHardwareCost(node)
+	if !override || NodeCapacityType(node) != CapacitySpot {
+		return hardwareCost
+	}
+	if onDemand := c.cfg.Pricing.InstanceMonthlyByType[instanceType]; onDemand > 0 {
+		return onDemand
+	}
+	return hardwareCost
+}
+
+// summarizeSpot sets the report's spot node count, share and savings.
+// Fargate virtual nodes are not instances and do not count toward the share.
+func summarizeSpot(report *Report) {
+	for _, node := range report.Nodes {
+		if node.CapacityType == CapacityFargate {
+			continue
+		}
+		report.InstanceNodes++
+		if node.CapacityType != CapacitySpot {
+			continue
+		}
+		report.SpotNodes++
+		report.SpotSavings += node.OnDemandCost - node.HardwareCost
+	}
+	if report.InstanceNodes > 0 {
+		report.SpotShare = float64(report.SpotNodes) / float64(report.InstanceNodes)
+	}
+}
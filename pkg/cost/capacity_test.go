@@ -0,0 +1,113 @@
+package cost
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/pricing"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNodeCapacityType(t *testing.T) {
+	cases := map[string]struct {
+		labels map[string]string
+		want   string
+	}{
+		"karpenter spot":      {map[string]string{karpenterCapacityTypeLabel: "spot"}, CapacitySpot},
+		"karpenter on-demand": {map[string]string{karpenterCapacityTypeLabel: "on-demand"}, CapacityOnDemand},
+		"eks spot":            {map[string]string{eksCapacityTypeLabel: "SPOT"}, CapacitySpot},
+		"eks on-demand":       {map[string]string{eksCapacityTypeLabel: "ON_DEMAND"}, CapacityOnDemand},
+		"unlabeled":           {nil, ""},
+		"unknown value":       {map[string]string{karpenterCapacityTypeLabel: "reserved"}, ""},
+	}
+	for name, tc := range cases {
+		if got := NodeCapacityType(testNode("n", "1Gi", tc.labels)); got != tc.want {
+			t.Fatalf("%s: expected %q, got %q", name, tc.want, got)
+		}
+	}
+}
+
+func TestNodeHardwareCost_Spot(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.InstanceMonthlyByType = map[string]float64{"m5.large": 70, "c5.large": 60}
+	cfg.Pricing.SpotMonthlyByType = map[string]float64{"m5.large": 25}
+	cfg.Pricing.SpotMonthlyByZone = map[string]map[string]float64{"us-east-2a": {"m5.large": 22}}
+	cfg.Pricing.SpotDiscount = 0.5
+	calc := newTestCalculator(t, cfg)
+
+	node := func(instanceType, capacity, zone string) corev1.Node {
+		return testNode("n", "8Gi", map[string]string{
+			nodeInstanceTypeLabel:      instanceType,
+			karpenterCapacityTypeLabel: capacity,
+			zoneLabel:                  zone,
+		})
+	}
+	cases := []struct {
+		name     string
+		node     corev1.Node
+		want     float64
+		onDemand float64
+	}{
+		{"on-demand", node("m5.large", "on-demand", "us-east-2a"), 70, 70},
+		{"spot by zone", node("m5.large", "spot", "us-east-2a"), 22, 70},
+		{"spot by type", node("m5.large", "spot", "us-east-2b"), 25, 70},
+		{"spot discount", node("c5.large", "spot", "us-east-2a"), 30, 60},
+	}
+	for _, tc := range cases {
+		got, _, override := calc.NodeHardwareCost(tc.node)
+		if !override || !approxEqual(got, tc.want) {
+			t.Fatalf("%s: expected hardware cost %.2f from the instance tables, got %.2f (override %v)", tc.name, tc.want, got, override)
+		}
+		if onDemand := calc.NodeOnDemandCost(tc.node); !approxEqual(onDemand, tc.onDemand) {
+			t.Fatalf("%s: expected on-demand cost %.2f, got %.2f", tc.name, tc.onDemand, onDemand)
+		}
+	}
+}
+
+func TestCalculate_SpotSummary(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.InstanceMonthlyByType = map[string]float64{"m5.large": 70}
+	cfg.Pricing.SpotMonthlyByType = map[string]float64{"m5.large": 25}
+	calc := newTestCalculator(t, cfg)
+
+	report := calc.Calculate(Inventory{Nodes: []corev1.Node{
+		testNode("a", "8Gi", map[string]string{nodeInstanceTypeLabel: "m5.large", eksCapacityTypeLabel: "SPOT"}),
+		testNode("b", "8Gi", map[string]string{nodeInstanceTypeLabel: "m5.large", eksCapacityTypeLabel: "ON_DEMAND"}),
+	}})
+	if report.SpotNodes != 1 || !approxEqual(report.SpotShare, 0.5) {
+		t.Fatalf("expected 1 spot node (50%%), got %d (%.2f)", report.SpotNodes, report.SpotShare)
+	}
+	if !approxEqual(report.SpotSavings, 45) {
+		t.Fatalf("expected spot savings 45, got %.2f", report.SpotSavings)
+	}
+	if !approxEqual(report.HardwareCost, 95) {
+		t.Fatalf("expected hardware cost 95, got %.2f", report.HardwareCost)
+	}
+}
+
+func TestCalculate_SpotShareIgnoresFargateNodes(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.InstanceMonthlyByType = map[string]float64{"m5.large": 70}
+	calc := newTestCalculator(t, cfg)
+
+	report := calc.Calculate(Inventory{Nodes: []corev1.Node{
+		testNode("a", "8Gi", map[string]string{nodeInstanceTypeLabel: "m5.large", karpenterCapacityTypeLabel: "spot"}),
+		testNode("b", "8Gi", map[string]string{nodeInstanceTypeLabel: "m5.large", karpenterCapacityTypeLabel: "on-demand"}),
+		testNode("fargate-ip-10-0-0-1", "4Gi", map[string]string{fargateComputeTypeLabel: "fargate"}),
+		testNode("fargate-ip-10-0-0-2", "4Gi", map[string]string{fargateComputeTypeLabel: "fargate"}),
+	}})
+	if report.SpotNodes != 1 || report.InstanceNodes != 2 || !approxEqual(report.SpotShare, 0.5) {
+		t.Fatalf("expected 1 of 2 instance nodes on spot (50%%), got %d/%d (%.2f)", report.SpotNodes, report.InstanceNodes, report.SpotShare)
+	}
+}
+
+func TestNewCalculator_InvalidSpotDiscount(t *testing.T) {
+	for _, discount := range []float64{-0.1, 1} {
+		cfg := config.DefaultConfig()
+		cfg.Pricing.SpotDiscount = discount
+		if _, err := NewCalculator(context.Background(), cfg, pricing.NewStaticProvider(0.02, 0.005)); err == nil {
+			t.Fatalf("expected error for spot_discount %g", discount)
+		}
+	}
+}
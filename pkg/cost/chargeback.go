@@ -0,0 +1,230 @@
+package cost
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/newman-bot/kfin/pkg/config"
+)
+
+// Chargeback methods for spreading shared cost across tenant namespaces.
+const (
+	ChargebackEven         = "even"
+	ChargebackProportional = "proportional"
+	ChargebackWeights      = "weights"
+)
+
+// chargebackPolicy is a validated config.ChargebackConfig.
+type chargebackPolicy struct {
+	method  string
+	shared  map[string]bool
+	weights map[string]float64
+}
+
+func parseChargeback(cfg config.ChargebackConfig) (*chargebackPolicy, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	method := strings.ToLower(strings.TrimSpace(cfg.Method))
+	switch method {
+	case "":
+		method = ChargebackProportional
+	case ChargebackEven, ChargebackProportional, ChargebackWeights:
+	default:
+		return nil, fmt.Errorf("invalid pricing.chargeback.method %q (expected: even, proportional or weights)", cfg.Method)
+	}
+	for ns, w := range cfg.Weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("pricing.chargeback.weights: %s must be a non-negative number, got %g", ns, w)
+		}
+	}
+	shared := make(map[string]bool, len(cfg.SharedNamespaces))
+	for _, ns := range cfg.SharedNamespaces {
+		shared[ns] = true
+	}
+	return &chargebackPolicy{method: method, shared: shared, weights: cfg.Weights}, nil
+}
+
+// Chargeback splits the cluster total across tenant namespaces: each tenant
+// pays its direct cost (pods, storage, load balancers and network) plus a
+// share of the shared pool. The pool is the cost of the shared namespaces,
+// the node-cost idle bucket, the control plane, and whatever node cost pod
+// pricing left unallocated; under cloud-rates that includes idle capacity.
+// When cloud-rate pod prices exceed what the nodes cost, every namespace's
+// cost is scaled down until they fit the cluster total and the excess is
+// reported as OverAllocatedCost, so the pool is never negative. All amounts
+// are whole cents, and Tenants, when there are any, add up exactly to
+// TotalCost, the cluster total.
+type Chargeback struct {
+	Method           string
+	SharedNamespaces []string
+
+	SharedNamespaceCost float64
+	IdleCost            float64
+	ControlPlaneCost    float64
+	UnallocatedCost     float64
+	OverAllocatedCost   float64
+	SharedCost          float64
+
+	Tenants   []ChargebackLine
+	TotalCost float64
+}
+
+// ChargebackLine is one tenant's invoice line.
+type ChargebackLine struct {
+	Namespace string
+	Direct    float64
+	Shared    float64
+	Total     float64
+}
+
+// chargeback builds the report's Chargeback from its namespace rollup, or
+// returns nil when no chargeback policy is configured. When every namespace
+// is shared or idle, Tenants is empty and there is nothing to charge back.
+func (c *Calculator) chargeback(report *Report) *Chargeback {
+	p := c.chargebackPolicy
+	if p == nil {
+		return nil
+	}
+
+	total := toCents(report.TotalCost)
+	controlPlane := toCents(report.ControlPlaneCost)
+	amounts := make([]int64, len(report.Namespaces))
+	weights := make([]float64, len(report.Namespaces))
+	var namespaced int64
+	for i, ns := range report.Namespaces {
+		amounts[i] = toCents(ns.Cost)
+		weights[i] = float64(amounts[i])
+		namespaced += amounts[i]
+	}
+	unallocated := total - controlPlane - namespaced
+	var overAllocated int64
+	if unallocated < 0 {
+		// Pod prices exceed the node cost: scale namespaces down to what is
+		// left of the total after the control plane.
+		overAllocated, unallocated = -unallocated, 0
+		amounts = splitCents(max(total-controlPlane, 0), weights)
+	}
+
+	var tenants []NamespaceCost
+	var direct []int64
+	var shared, idle int64
+	var sharedNames []string
+	for i, ns := range report.Namespaces {
+		amount := amounts[i]
+		switch {
+		case ns.Name == IdleNamespace:
+			idle += amount
+		case p.shared[ns.Name]:
+			sharedNames = append(sharedNames, ns.Name)
+			shared += amount
+		default:
+			tenants = append(tenants, ns)
+			direct = append(direct, amount)
+		}
+	}
+	sort.Strings(sharedNames)
+
+	pool := shared + idle + controlPlane + unallocated
+
+	cb := &Chargeback{
+		Method:              p.method,
+		SharedNamespaces:    sharedNames,
+		SharedNamespaceCost: dollars(shared),
+		IdleCost:            dollars(idle),
+		ControlPlaneCost:    dollars(controlPlane),
+		UnallocatedCost:     dollars(unallocated),
+		OverAllocatedCost:   dollars(overAllocated),
+		SharedCost:          dollars(pool),
+		TotalCost:           dollars(total),
+	}
+	shares := splitCents(pool, p.tenantWeights(tenants, direct))
+	for i, t := range tenants {
+		cb.Tenants = append(cb.Tenants, ChargebackLine{
+			Namespace: t.Name,
+			Direct:    dollars(direct[i]),
+			Shared:    dollars(shares[i]),
+			Total:     dollars(direct[i] + shares[i]),
+		})
+	}
+	sort.SliceStable(cb.Tenants, func(i, j int) bool {
+		if cb.Tenants[i].Total != cb.Tenants[j].Total {
+			return cb.Tenants[i].Total > cb.Tenants[j].Total
+		}
+		return cb.Tenants[i].Namespace < cb.Tenants[j].Namespace
+	})
+	return cb
+}
+
+// tenantWeights returns each tenant's weight under the policy's method.
+// Proportional and weights fall back to an even split when every weight is
+// zero.
+func (p *chargebackPolicy) tenantWeights(tenants []NamespaceCost, direct []int64) []float64 {
+	weights := make([]float64, len(tenants))
+	var sum float64
+	for i, t := range tenants {
+		switch p.method {
+		case ChargebackProportional:
+			weights[i] = math.Max(float64(direct[i]), 0)
+		case ChargebackWeights:
+			weights[i] = p.weights[t.Name]
+		default:
+			weights[i] = 1
+		}
+		sum += weights[i]
+	}
+	if sum == 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	return weights
+}
+
+// splitCents divides amount cents by weights using the largest remainder
+// method, so the parts always add up to amount.
+func splitCents(amount int64, weights []float64) []int64 {
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	parts := make([]int64, len(weights))
+	if sum == 0 {
+		return parts
+	}
+
+	sign := int64(1)
+	if amount < 0 {
+		sign, amount = -1, -amount
+	}
+	type remainder struct {
+		i    int
+		frac float64
+	}
+	rems := make([]remainder, len(weights))
+	var assigned int64
+	for i, w := range weights {
+		exact := float64(amount) * w / sum
+		parts[i] = int64(math.Floor(exact))
+		assigned += parts[i]
+		rems[i] = remainder{i, exact - float64(parts[i])}
+	}
+	sort.SliceStable(rems, func(a, b int) bool { return rems[a].frac > rems[b].frac })
+	for k := int64(0); k < amount-assigned; k++ {
+		parts[rems[int(k)%len(rems)].i]++
+	}
+	for i := range parts {
+		parts[i] *= sign
+	}
+	return parts
+}
+
+func toCents(v float64) int64 {
+	return int64(math.Round(v * 100))
+}
+
+func dollars(cents int64) float64 {
+	return float64(cents) / 100
+}
@@ -0,0 +1,242 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/stats"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func chargebackReport(t *testing.T, mode string, cb config.ChargebackConfig) *Report {
+	t.Helper()
+	return chargebackReportWithNetwork(t, mode, cb, nil)
+}
+
+func chargebackReportWithNetwork(t *testing.T, mode string, cb config.ChargebackConfig, tx map[stats.PodKey]stats.Aggregate) *Report {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Pricing.Allocation.Mode = mode
+	cfg.Pricing.EKS.ControlPlanePerHour = 0.10
+	cb.Enabled = true
+	cfg.Pricing.Chargeback = cb
+	calc := newTestCalculator(t, cfg)
+
+	placed := func(ns, name, cpu, mem string) corev1.Pod {
+		pod := testPod(ns, name, testContainer("app", cpu, mem))
+		pod.Spec.NodeName = "n1"
+		return pod
+	}
+	node := testNode("n1", "16Gi", nil)
+	node.Labels = map[string]string{"eks.amazonaws.com/nodegroup": "ng"}
+	return calc.Calculate(Inventory{
+		Pods: []corev1.Pod{
+			placed("shop", "a", "2", "2Gi"),
+			placed("search", "b", "1", "1Gi"),
+			placed("batch", "c", "333m", "100Mi"),
+			placed("kube-system", "d", "500m", "512Mi"),
+		},
+		Nodes:           []corev1.Node{node},
+		NetworkTransmit: tx,
+	})
+}
+
+func chargebackSum(cb *Chargeback) int64 {
+	var sum int64
+	for _, t := range cb.Tenants {
+		sum += toCents(t.Total)
+	}
+	return sum
+}
+
+func TestChargeback_TenantsAddUpToTotal(t *testing.T) {
+	for _, mode := range []string{string(AllocationCloudRates), string(AllocationNodeCost)} {
+		for _, method := range []string{ChargebackEven, ChargebackProportional, ChargebackWeights} {
+			report := chargebackReport(t, mode, config.ChargebackConfig{
+				SharedNamespaces: []string{"kube-system"},
+				Method:           method,
+				Weights:          map[string]float64{"shop": 3, "search": 1},
+			})
+			cb := report.Chargeback
+			if cb == nil || len(cb.Tenants) != 3 {
+				t.Fatalf("%s/%s: expected 3 tenants, got %+v", mode, method, cb)
+			}
+			if got, want := chargebackSum(cb), toCents(report.TotalCost); got != want || toCents(cb.TotalCost) != want {
+				t.Fatalf("%s/%s: tenants add up to %d cents, cluster total is %d", mode, method, got, want)
+			}
+			if mode == string(AllocationNodeCost) && cb.OverAllocatedCost != 0 {
+				t.Fatalf("%s/%s: expected no over-allocation when pods split node cost, got %.2f", mode, method, cb.OverAllocatedCost)
+			}
+			if cb.ControlPlaneCost <= 0 || cb.SharedNamespaceCost <= 0 {
+				t.Fatalf("%s/%s: expected control plane and kube-system in the pool, got %+v", mode, method, cb)
+			}
+			pool := toCents(cb.SharedNamespaceCost) + toCents(cb.IdleCost) + toCents(cb.ControlPlaneCost) + toCents(cb.UnallocatedCost)
+			if pool != toCents(cb.SharedCost) {
+				t.Fatalf("%s/%s: pool components add up to %d cents, pool is %v", mode, method, pool, cb.SharedCost)
+			}
+		}
+	}
+}
+
+func TestChargeback_NetworkBilledOnce(t *testing.T) {
+	// 100 GB per month out of shop, all of it internet egress.
+	rate := 100.0 * BytesPerGB / secondsPerMonth
+	tx := map[stats.PodKey]stats.Aggregate{{Namespace: "shop", Name: "a"}: {Avg: rate}}
+	for _, mode := range []string{string(AllocationCloudRates), string(AllocationNodeCost)} {
+		report := chargebackReportWithNetwork(t, mode, config.ChargebackConfig{SharedNamespaces: []string{"kube-system"}}, tx)
+		if report.NetworkCost <= 0 {
+			t.Fatalf("%s: expected network cost in the report", mode)
+		}
+		cb := report.Chargeback
+		if got, want := chargebackSum(cb), toCents(report.TotalCost); got != want {
+			t.Fatalf("%s: tenants add up to %d cents, cluster total is %d", mode, got, want)
+		}
+		for _, line := range cb.Tenants {
+			if line.Namespace != "shop" {
+				continue
+			}
+			// Over-allocated cloud rates scale direct cost down, so it may
+			// be below the namespace cost but never above it.
+			for _, ns := range report.Namespaces {
+				if ns.Name != "shop" {
+					continue
+				}
+				if d, c := toCents(line.Direct), toCents(ns.Cost); d > c || (cb.OverAllocatedCost == 0 && d != c) {
+					t.Fatalf("%s: expected shop billed its namespace cost %.2f once, got %.2f", mode, ns.Cost, line.Direct)
+				}
+			}
+		}
+		if mode == string(AllocationNodeCost) && cb.UnallocatedCost < 0 {
+			t.Fatalf("%s: expected no negative unallocated cost, got %.2f", mode, cb.UnallocatedCost)
+		}
+	}
+}
+
+func TestChargeback_Methods(t *testing.T) {
+	shares := func(method string) map[string]ChargebackLine {
+		report := chargebackReport(t, string(AllocationNodeCost), config.ChargebackConfig{
+			SharedNamespaces: []string{"kube-system"},
+			Method:           method,
+			Weights:          map[string]float64{"shop": 1},
+		})
+		out := make(map[string]ChargebackLine)
+		for _, line := range report.Chargeback.Tenants {
+			out[line.Namespace] = line
+		}
+		return out
+	}
+
+	even := shares(ChargebackEven)
+	if d := toCents(even["shop"].Shared) - toCents(even["batch"].Shared); d < -1 || d > 1 {
+		t.Fatalf("even: expected equal shares, got %+v", even)
+	}
+
+	prop := shares(ChargebackProportional)
+	if prop["shop"].Shared <= prop["search"].Shared || prop["search"].Shared <= prop["batch"].Shared {
+		t.Fatalf("proportional: expected shares ordered by direct cost, got %+v", prop)
+	}
+
+	weighted := shares(ChargebackWeights)
+	if weighted["search"].Shared != 0 || weighted["batch"].Shared != 0 {
+		t.Fatalf("weights: expected unlisted tenants to pay no share, got %+v", weighted)
+	}
+	if weighted["shop"].Total != weighted["shop"].Direct+weighted["shop"].Shared {
+		t.Fatalf("weights: expected total to be direct plus shared, got %+v", weighted["shop"])
+	}
+}
+
+func TestChargeback_Disabled(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+	pod := testPod("shop", "a", testContainer("app", "1", "1Gi"))
+	report := calc.Calculate(Inventory{Pods: []corev1.Pod{pod}})
+	if report.Chargeback != nil {
+		t.Fatalf("expected no chargeback by default, got %+v", report.Chargeback)
+	}
+}
+
+func TestParseChargeback_Invalid(t *testing.T) {
+	if _, err := parseChargeback(config.ChargebackConfig{Enabled: true, Method: "random"}); err == nil {
+		t.Fatalf("expected error for unknown method")
+	}
+	if _, err := parseChargeback(config.ChargebackConfig{Enabled: true, Weights: map[string]float64{"shop": -1}}); err == nil {
+		t.Fatalf("expected error for negative weight")
+	}
+}
+
+func TestSplitCents(t *testing.T) {
+	parts := splitCents(100, []float64{1, 1, 1})
+	if parts[0]+parts[1]+parts[2] != 100 {
+		t.Fatalf("expected parts to add up to 100, got %v", parts)
+	}
+	parts = splitCents(-7, []float64{1, 2})
+	if parts[0]+parts[1] != -7 {
+		t.Fatalf("expected parts to add up to -7, got %v", parts)
+	}
+}
+
+func TestChargeback_OverAllocatedScaledToTotal(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.Allocation.Mode = string(AllocationCloudRates)
+	cfg.Pricing.Chargeback = config.ChargebackConfig{Enabled: true, SharedNamespaces: []string{"kube-system"}}
+	calc := newTestCalculator(t, cfg)
+
+	// Cloud-rate prices of these requests are far above what the one node
+	// costs, so nothing of the node is left unallocated.
+	placed := func(ns, name, cpu string) corev1.Pod {
+		pod := testPod(ns, name, testContainer("app", cpu, "64Gi"))
+		pod.Spec.NodeName = "n1"
+		return pod
+	}
+	report := calc.Calculate(Inventory{
+		Pods: []corev1.Pod{
+			placed("shop", "a", "64"),
+			placed("search", "b", "32"),
+			placed("kube-system", "d", "1"),
+		},
+		Nodes: []corev1.Node{testNode("n1", "16Gi", nil)},
+	})
+
+	cb := report.Chargeback
+	var namespaced float64
+	for _, ns := range report.Namespaces {
+		namespaced += ns.Cost
+	}
+	excess := toCents(namespaced) + toCents(report.ControlPlaneCost) - toCents(report.TotalCost)
+	if excess <= 0 {
+		t.Fatalf("expected pod prices above the cluster total, got %.2f of %.2f", namespaced, report.TotalCost)
+	}
+	if cb.UnallocatedCost != 0 || toCents(cb.OverAllocatedCost) != excess {
+		t.Fatalf("expected no unallocated cost and %d cents over-allocated, got %+v", excess, cb)
+	}
+	for _, line := range cb.Tenants {
+		if line.Shared < 0 {
+			t.Fatalf("expected no negative shared charge, got %+v", line)
+		}
+	}
+	if got, want := chargebackSum(cb), toCents(report.TotalCost); got != want || toCents(cb.TotalCost) != want {
+		t.Fatalf("expected tenants to add up to the cluster total %d cents (chargeback total %.2f), got %d", want, cb.TotalCost, got)
+	}
+	// Direct costs are scaled down by the same factor to fit the total.
+	direct := make(map[string]float64)
+	for _, line := range cb.Tenants {
+		direct[line.Namespace] = line.Direct
+	}
+	for _, ns := range report.Namespaces {
+		if d, ok := direct[ns.Name]; ok && d >= ns.Cost {
+			t.Fatalf("expected %s direct cost scaled below its namespace cost %.2f, got %.2f", ns.Name, ns.Cost, d)
+		}
+	}
+}
+
+func TestChargeback_NoTenants(t *testing.T) {
+	report := chargebackReport(t, string(AllocationNodeCost), config.ChargebackConfig{
+		SharedNamespaces: []string{"shop", "search", "batch", "kube-system"},
+	})
+	cb := report.Chargeback
+	if cb == nil {
+		t.Fatalf("expected a chargeback without tenants when it is enabled")
+	}
+	if len(cb.Tenants) != 0 || cb.SharedNamespaceCost <= 0 || len(cb.SharedNamespaces) != 4 {
+		t.Fatalf("expected every namespace in the shared pool and no tenants, got %+v", cb)
+	}
+}
@@ -0,0 +1,110 @@
+package cost
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Distribution is the Kubernetes distribution a cluster runs, detected from
+// node metadata. It selects the control plane price.
+type Distribution string
+
+const (
+	DistributionUnknown Distribution = ""
+	DistributionEKS     Distribution = "eks"
+	DistributionGKE     Distribution = "gke"
+	DistributionAKS     Distribution = "aks"
+	DistributionK3s     Distribution = "k3s"
+	DistributionRKE2    Distribution = "rke2"
+)
+
+// String returns the distribution name, or "kubernetes" when unknown.
+func (d Distribution) String() string {
+	if d == DistributionUnknown {
+		return "kubernetes"
+	}
+	return string(d)
+}
+
+// DetectDistribution returns the distribution of the first node that carries
+// a recognizable provider ID, label prefix or kubelet version suffix. k3s and
+// RKE2 win over the cloud markers, since they also run on cloud VMs:
+//   - EKS: aws:// provider ID or eks.amazonaws.com/ labels
+//   - GKE: gce:// provider ID or cloud.google.com/gke- labels
+//   - AKS: azure:// provider ID or kubernetes.azure.com/ labels
+//   - k3s and RKE2: a +k3s or +rke2 kubelet version, or the instance type
+//     label k3s and RKE2 set on their nodes
+func DetectDistribution(nodes []corev1.Node) Distribution {
+	for _, node := range nodes {
+		if d := nodeDistribution(node); d != DistributionUnknown {
+			return d
+		}
+	}
+	return DistributionUnknown
+}
+
+func nodeDistribution(node corev1.Node) Distribution {
+	// Self-managed k3s and RKE2 often run on cloud VMs, whose cloud provider
+	// sets an aws://, gce:// or azure:// provider ID, so check them first.
+	providerID := node.Spec.ProviderID
+	kubelet := node.Status.NodeInfo.KubeletVersion
+	instanceType := node.Labels[nodeInstanceTypeLabel]
+	switch {
+	case strings.Contains(kubelet, "+k3s") || instanceType == "k3s" || strings.HasPrefix(providerID, "k3s://"):
+		return DistributionK3s
+	case strings.Contains(kubelet, "+rke2") || instanceType == "rke2" || strings.HasPrefix(providerID, "rke2://"):
+		return DistributionRKE2
+	}
+
+	switch {
+	case strings.HasPrefix(providerID, "aws://") || hasLabelPrefix(node, "eks.amazonaws.com/"):
+		return DistributionEKS
+	case strings.HasPrefix(providerID, "gce://") || hasLabelPrefix(node, "cloud.google.com/gke-"):
+		return DistributionGKE
+	case strings.HasPrefix(providerID, "azure://") || hasLabelPrefix(node, "kubernetes.azure.com/"):
+		return DistributionAKS
+	}
+	return DistributionUnknown
+}
+
+func hasLabelPrefix(node corev1.Node, prefix string) bool {
+	for k := range node.Labels {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEKSCluster reports whether any node carries EKS labels or an AWS provider
+// ID. It is about the AWS cloud provider's load balancers, so a k3s or RKE2
+// cluster on EC2 counts too.
+func IsEKSCluster(nodes []corev1.Node) bool {
+	for _, node := range nodes {
+		if strings.HasPrefix(node.Spec.ProviderID, "aws://") || hasLabelPrefix(node, "eks.amazonaws.com/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ControlPlanePerHour returns the hourly control plane price configured for
+// d: pricing.eks, pricing.gke, pricing.aks, pricing.k3s or pricing.rke2.
+// Unknown distributions have no managed control plane to price.
+func (c *Calculator) ControlPlanePerHour(d Distribution) float64 {
+	p := c.cfg.Pricing
+	switch d {
+	case DistributionEKS:
+		return p.EKS.ControlPlanePerHour
+	case DistributionGKE:
+		return p.GKE.ControlPlanePerHour
+	case DistributionAKS:
+		return p.AKS.ControlPlanePerHour
+	case DistributionK3s:
+		return p.K3s.ControlPlanePerHour
+	case DistributionRKE2:
+		return p.RKE2.ControlPlanePerHour
+	}
+	return 0
+}
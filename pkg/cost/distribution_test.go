@@ -0,0 +1,82 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDetectDistribution(t *testing.T) {
+	withProvider := func(id string) corev1.Node {
+		node := testNode("n", "1Gi", nil)
+		node.Spec.ProviderID = id
+		return node
+	}
+	withKubelet := func(version string) corev1.Node {
+		node := testNode("n", "1Gi", nil)
+		node.Status.NodeInfo.KubeletVersion = version
+		return node
+	}
+	onCloud := func(id, version string) corev1.Node {
+		node := withProvider(id)
+		node.Status.NodeInfo.KubeletVersion = version
+		return node
+	}
+	cases := map[string]struct {
+		node corev1.Node
+		want Distribution
+	}{
+		"eks provider":  {withProvider("aws:///us-east-2a/i-0abc"), DistributionEKS},
+		"eks label":     {testNode("n", "1Gi", map[string]string{"eks.amazonaws.com/nodegroup": "ng"}), DistributionEKS},
+		"gke provider":  {withProvider("gce://project/us-central1-a/gke-node"), DistributionGKE},
+		"gke label":     {testNode("n", "1Gi", map[string]string{"cloud.google.com/gke-nodepool": "pool"}), DistributionGKE},
+		"aks provider":  {withProvider("azure:///subscriptions/x/resourceGroups/y"), DistributionAKS},
+		"aks label":     {testNode("n", "1Gi", map[string]string{"kubernetes.azure.com/cluster": "c"}), DistributionAKS},
+		"k3s kubelet":   {withKubelet("v1.30.4+k3s1"), DistributionK3s},
+		"k3s label":     {testNode("n", "1Gi", map[string]string{nodeInstanceTypeLabel: "k3s"}), DistributionK3s},
+		"rke2 kubelet":  {withKubelet("v1.30.4+rke2r1"), DistributionRKE2},
+		"plain kubeadm": {withKubelet("v1.30.4"), DistributionUnknown},
+		"rke2 on ec2":   {onCloud("aws:///us-east-2a/i-0abc", "v1.30.4+rke2r1"), DistributionRKE2},
+		"k3s on gce":    {onCloud("gce://project/us-central1-a/vm", "v1.30.4+k3s1"), DistributionK3s},
+		"rke2 on azure": {onCloud("azure:///subscriptions/x/resourceGroups/y", "v1.30.4+rke2r1"), DistributionRKE2},
+	}
+	for name, tc := range cases {
+		if got := DetectDistribution([]corev1.Node{tc.node}); got != tc.want {
+			t.Fatalf("%s: expected %q, got %q", name, tc.want, got)
+		}
+	}
+	if !IsEKSCluster([]corev1.Node{cases["rke2 on ec2"].node}) {
+		t.Fatalf("expected RKE2 on EC2 to keep AWS load balancer pricing")
+	}
+}
+
+func TestCalculate_ControlPlaneByDistribution(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.EKS.ControlPlanePerHour = 0.10
+	cfg.Pricing.GKE.ControlPlanePerHour = 0.20
+	cfg.Pricing.AKS.ControlPlanePerHour = 0.30
+	cfg.Pricing.K3s.ControlPlanePerHour = 0.01
+	calc := newTestCalculator(t, cfg)
+
+	cases := []struct {
+		labels map[string]string
+		want   Distribution
+		rate   float64
+	}{
+		{map[string]string{"eks.amazonaws.com/nodegroup": "ng"}, DistributionEKS, 0.10},
+		{map[string]string{"cloud.google.com/gke-nodepool": "pool"}, DistributionGKE, 0.20},
+		{map[string]string{"kubernetes.azure.com/cluster": "c"}, DistributionAKS, 0.30},
+		{map[string]string{nodeInstanceTypeLabel: "k3s"}, DistributionK3s, 0.01},
+		{nil, DistributionUnknown, 0},
+	}
+	for _, tc := range cases {
+		report := calc.Calculate(Inventory{Nodes: []corev1.Node{testNode("n", "1Gi", tc.labels)}})
+		if report.Distribution != tc.want {
+			t.Fatalf("expected distribution %q, got %q", tc.want, report.Distribution)
+		}
+		if !approxEqual(report.ControlPlaneCost, tc.rate*HoursPerMonth) {
+			t.Fatalf("%s: expected control plane %.2f, got %.2f", tc.want, tc.rate*HoursPerMonth, report.ControlPlaneCost)
+		}
+	}
+}
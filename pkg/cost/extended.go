@@ -0,0 +1,80 @@
+package cost
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// isExtended reports whether name is priced through pricing.UsageRates.Extended
+// rather than the CPU and memory rates.
+func isExtended(name corev1.ResourceName) bool {
+	return name != corev1.ResourceCPU && name != corev1.ResourceMemory
+}
+
+// isByteResource reports whether name is measured in bytes and so priced per
+// GB rather than per unit.
+func isByteResource(name corev1.ResourceName) bool {
+	return name == corev1.ResourceEphemeralStorage || strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix)
+}
+
+// mergeExtendedRates returns rates with any resource missing from it filled in
+// from defaults, so a pricing provider only has to know the resources it
+// prices differently.
+func mergeExtendedRates(rates, defaults map[string]float64) map[string]float64 {
+	out := make(map[string]float64, len(rates)+len(defaults))
+	for name, rate := range defaults {
+		out[name] = rate
+	}
+	for name, rate := range rates {
+		out[name] = rate
+	}
+	return out
+}
+
+// extendedResources returns the extended resources in rl that have a price.
+func (c *Calculator) extendedResources(rl corev1.ResourceList) corev1.ResourceList {
+	out := corev1.ResourceList{}
+	for name, q := range rl {
+		if !isExtended(name) || q.IsZero() || c.rates.Extended[string(name)] <= 0 {
+			continue
+		}
+		out[name] = q.DeepCopy()
+	}
+	return out
+}
+
+// ExtendedCost returns the monthly cost of the extended resources in rl at the
+// calculator's rates. Resources without a rate are free.
+func (c *Calculator) ExtendedCost(rl corev1.ResourceList) float64 {
+	var total float64
+	for name, q := range rl {
+		if !isExtended(name) {
+			continue
+		}
+		units := float64(q.MilliValue()) / 1000.0
+		if isByteResource(name) {
+			units = float64(q.Value()) / BytesPerGB
+		}
+		total += units * HoursPerMonth * c.rates.Extended[string(name)]
+	}
+	return total
+}
+
+// extendedNames returns the sorted names of every extended resource billed to
+// at least one pod.
+func extendedNames(pods []PodCost) []corev1.ResourceName {
+	seen := make(map[corev1.ResourceName]bool)
+	var names []corev1.ResourceName
+	for _, p := range pods {
+		for name := range p.Extended {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
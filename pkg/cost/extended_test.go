@@ -0,0 +1,90 @@
+package cost
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/pricing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type extendedProvider struct {
+	rates pricing.UsageRates
+}
+
+func (p extendedProvider) UsageRates(context.Context) (pricing.UsageRates, error) {
+	return p.rates, nil
+}
+func (p extendedProvider) Source() string { return "test" }
+
+func gpuContainer(name, cpu, mem string, extra corev1.ResourceList) corev1.Container {
+	c := testContainer(name, cpu, mem)
+	for k, v := range extra {
+		c.Resources.Requests[k] = v
+	}
+	return c
+}
+
+func TestCalculate_ExtendedResources(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.Cloud.ExtendedPerHour = map[string]float64{
+		"nvidia.com/gpu": 2.0,
+		"hugepages-2Mi":  0.01,
+	}
+	calc := newTestCalculator(t, cfg)
+
+	train := testPod("ml", "train", gpuContainer("trainer", "1", "1Gi", corev1.ResourceList{
+		"nvidia.com/gpu":                resource.MustParse("2"),
+		"hugepages-2Mi":                 resource.MustParse("4Gi"),
+		corev1.ResourceEphemeralStorage: resource.MustParse("10Gi"),
+	}))
+	web := testPod("web", "api", testContainer("app", "1", "1Gi"))
+
+	report := calc.Calculate(Inventory{Pods: []corev1.Pod{train, web}})
+
+	base := HoursPerMonth*0.02 + HoursPerMonth*0.005
+	wantExt := 2*HoursPerMonth*2.0 + 4*HoursPerMonth*0.01
+	ml := report.Pods[0]
+	if !approxEqual(ml.ExtendedCost, wantExt) || !approxEqual(ml.Cost, base+wantExt) {
+		t.Fatalf("expected extended cost %.4f (total %.4f), got %.4f (total %.4f)", wantExt, base+wantExt, ml.ExtendedCost, ml.Cost)
+	}
+	if _, ok := ml.Extended[corev1.ResourceEphemeralStorage]; ok {
+		t.Fatalf("expected unpriced ephemeral-storage to be left out, got %v", ml.Extended)
+	}
+	if !approxEqual(report.Pods[1].Cost, base) {
+		t.Fatalf("expected plain pod cost %.4f, got %.4f", base, report.Pods[1].Cost)
+	}
+
+	want := []corev1.ResourceName{"hugepages-2Mi", "nvidia.com/gpu"}
+	if len(report.ExtendedResources) != 2 || report.ExtendedResources[0] != want[0] || report.ExtendedResources[1] != want[1] {
+		t.Fatalf("expected extended resources %v, got %v", want, report.ExtendedResources)
+	}
+	for _, ns := range report.Namespaces {
+		if ns.Name == "ml" {
+			if gpus := ns.Extended["nvidia.com/gpu"]; gpus.Value() != 2 {
+				t.Fatalf("expected 2 gpus in ml namespace, got %s", gpus.String())
+			}
+		}
+	}
+}
+
+func TestNewCalculator_ExtendedRatesFallBackToConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.Cloud.ExtendedPerHour = map[string]float64{"nvidia.com/gpu": 2.0, "amd.com/gpu": 1.5}
+	provider := extendedProvider{rates: pricing.UsageRates{
+		CPUPerHour:   0.02,
+		MemPerGBHour: 0.005,
+		Extended:     map[string]float64{"nvidia.com/gpu": 3.0},
+	}}
+
+	calc, err := NewCalculator(context.Background(), cfg, provider)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	rates := calc.Rates().Extended
+	if rates["nvidia.com/gpu"] != 3.0 || rates["amd.com/gpu"] != 1.5 {
+		t.Fatalf("expected provider rates to override config, got %v", rates)
+	}
+}
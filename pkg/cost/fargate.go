@@ -0,0 +1,93 @@
+package cost
+
+import (
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// CapacityFargate marks EKS Fargate nodes in NodeCost.CapacityType.
+const CapacityFargate = "fargate"
+
+const fargateComputeTypeLabel = "eks.amazonaws.com/compute-type"
+
+// fargateMemoryOverheadGB is added to every pod's memory request for the
+// kubelet, kube-proxy and containerd Fargate runs alongside it.
+const fargateMemoryOverheadGB = 0.25
+
+// fargateSize is one Fargate vCPU option and the memory sizes it allows.
+type fargateSize struct {
+	vcpu                 float64
+	minGB, maxGB, stepGB float64
+}
+
+// fargateSizes lists the vCPU and memory combinations Fargate provisions,
+// smallest first.
+var fargateSizes = []fargateSize{
+	{vcpu: 0.25, minGB: 0.5, maxGB: 0.5, stepGB: 0.5},
+	{vcpu: 0.25, minGB: 1, maxGB: 2, stepGB: 1},
+	{vcpu: 0.5, minGB: 1, maxGB: 4, stepGB: 1},
+	{vcpu: 1, minGB: 2, maxGB: 8, stepGB: 1},
+	{vcpu: 2, minGB: 4, maxGB: 16, stepGB: 1},
+	{vcpu: 4, minGB: 8, maxGB: 30, stepGB: 1},
+	{vcpu: 8, minGB: 16, maxGB: 60, stepGB: 4},
+	{vcpu: 16, minGB: 32, maxGB: 120, stepGB: 8},
+}
+
+// IsFargateNode reports whether node is a virtual EKS Fargate node.
+func IsFargateNode(node corev1.Node) bool {
+	return node.Labels[fargateComputeTypeLabel] == CapacityFargate
+}
+
+func fargateNodeNames(nodes []corev1.Node) map[string]bool {
+	names := make(map[string]bool)
+	for _, node := range nodes {
+		if IsFargateNode(node) {
+			names[node.Name] = true
+		}
+	}
+	return names
+}
+
+// FargateSize rounds a pod request up to the smallest Fargate configuration
+// that fits it, after adding Fargate's 256Mi memory overhead. Requests larger
+// than the biggest configuration are billed at that configuration.
+func FargateSize(cpu, mem resource.Quantity) (vcpu, memGB float64) {
+	wantCPU := float64(cpu.MilliValue()) / 1000
+	wantGB := float64(mem.Value())/BytesPerGB + fargateMemoryOverheadGB
+	for _, size := range fargateSizes {
+		if size.vcpu < wantCPU || size.maxGB < wantGB {
+			continue
+		}
+		gb := size.minGB
+		if wantGB > gb {
+			gb += math.Ceil((wantGB-gb)/size.stepGB) * size.stepGB
+		}
+		return size.vcpu, gb
+	}
+	last := fargateSizes[len(fargateSizes)-1]
+	return last.vcpu, last.maxGB
+}
+
+// FargateCost returns the monthly cost of a Fargate pod of the given size at
+// pricing.eks.fargate rates.
+func (c *Calculator) FargateCost(vcpu, memGB float64) float64 {
+	rates := c.cfg.Pricing.EKS.Fargate
+	return (vcpu*rates.VCPUPerHour + memGB*rates.GBPerHour) * HoursPerMonth
+}
+
+// priceFargatePod replaces the pod's cost with its Fargate price. Fargate
+// bills the effective request whatever the cost basis, so CPU and Memory
+// become the rounded-up size; extended resources are not available on
+// Fargate.
+func (c *Calculator) priceFargatePod(pc *PodCost) {
+	vcpu, memGB := FargateSize(pc.Requests.Effective.Cpu().DeepCopy(), pc.Requests.Effective.Memory().DeepCopy())
+	pc.Fargate = true
+	pc.Basis = BasisRequests
+	pc.CPU = *resource.NewMilliQuantity(int64(vcpu*1000), resource.DecimalSI)
+	pc.Memory = *resource.NewQuantity(int64(memGB*BytesPerGB), resource.BinarySI)
+	pc.Extended = nil
+	pc.ExtendedCost = 0
+	pc.Cost = c.FargateCost(vcpu, memGB)
+}
@@ -0,0 +1,67 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestFargateSize(t *testing.T) {
+	cases := []struct {
+		cpu, mem string
+		vcpu, gb float64
+	}{
+		{"100m", "128Mi", 0.25, 0.5},
+		{"250m", "512Mi", 0.25, 1},
+		{"300m", "1Gi", 0.5, 2},
+		{"1", "3.5Gi", 1, 4},
+		{"1500m", "2Gi", 2, 4},
+		{"3", "20Gi", 4, 21},
+		{"6", "40Gi", 8, 44},
+		{"16", "200Gi", 16, 120},
+	}
+	for _, tc := range cases {
+		vcpu, gb := FargateSize(resource.MustParse(tc.cpu), resource.MustParse(tc.mem))
+		if vcpu != tc.vcpu || !approxEqual(gb, tc.gb) {
+			t.Fatalf("%s/%s: expected %.2f vCPU %.2f GB, got %.2f vCPU %.2f GB", tc.cpu, tc.mem, tc.vcpu, tc.gb, vcpu, gb)
+		}
+	}
+}
+
+func TestCalculate_FargatePods(t *testing.T) {
+	for _, mode := range []string{"cloud-rates", "node-cost"} {
+		cfg := config.DefaultConfig()
+		cfg.Pricing.HardwareMonthlyPerGB = 1
+		cfg.Pricing.WattsPerNode = 0
+		cfg.Pricing.EKS.Fargate = config.FargatePricing{VCPUPerHour: 0.04, GBPerHour: 0.004}
+		cfg.Pricing.Allocation.Mode = mode
+		calc := newTestCalculator(t, cfg)
+
+		fargateNode := testNode("fargate-ip-10-0-0-1", "4Gi", map[string]string{fargateComputeTypeLabel: "fargate"})
+		pod := testPod("default", "api", testContainer("app", "500m", "1Gi"))
+		pod.Spec.NodeName = fargateNode.Name
+
+		report := calc.Calculate(Inventory{
+			Pods:  []corev1.Pod{pod},
+			Nodes: []corev1.Node{fargateNode, testNode("worker", "8Gi", nil)},
+		})
+		want := (0.5*0.04 + 2*0.004) * HoursPerMonth
+		if !report.Pods[0].Fargate || !approxEqual(report.Pods[0].Cost, want) {
+			t.Fatalf("%s: expected fargate pod cost %.4f, got %.4f (fargate %v)", mode, want, report.Pods[0].Cost, report.Pods[0].Fargate)
+		}
+		if !approxEqual(report.HardwareCost, 8) {
+			t.Fatalf("%s: expected fargate node excluded from hardware cost 8, got %.4f", mode, report.HardwareCost)
+		}
+		if !approxEqual(report.FargateCost, want) || report.FargatePods != 1 {
+			t.Fatalf("%s: expected fargate cost %.4f for 1 pod, got %.4f for %d", mode, want, report.FargateCost, report.FargatePods)
+		}
+		if !approxEqual(report.TotalCost, 8+want+report.ControlPlaneCost) {
+			t.Fatalf("%s: expected fargate cost in total, got %.4f", mode, report.TotalCost)
+		}
+		if report.Nodes[0].CapacityType != CapacityFargate || report.Nodes[0].TotalCost != 0 {
+			t.Fatalf("%s: expected zero-cost fargate node, got %+v", mode, report.Nodes[0])
+		}
+	}
+}
@@ -0,0 +1,435 @@
+package cost
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/stats"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Severity ranks findings; higher is more urgent.
+type Severity int
+
+const (
+	SeverityLow Severity = iota
+	SeverityMedium
+	SeverityHigh
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityHigh:
+		return "high"
+	case SeverityMedium:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// Finding rules.
+const (
+	RuleNoRequests         = "no-requests"
+	RuleOverRequested      = "over-requested"
+	RuleLimitsNoRequests   = "limits-without-requests"
+	RulePendingPod         = "pending-pod"
+	RuleLowNodeUtilization = "low-node-utilization"
+	RuleOrphanedClaim      = "orphaned-pvc"
+	RuleEmptyNamespace     = "empty-namespace"
+)
+
+// Monthly impact at which a finding is at least high or medium severity.
+const (
+	findingImpactHigh   = 100.0
+	findingImpactMedium = 10.0
+)
+
+// Namespaces every cluster has, often without pods; never flagged as empty.
+var builtinNamespaces = map[string]bool{
+	"default":         true,
+	"kube-public":     true,
+	"kube-node-lease": true,
+}
+
+// Finding is one waste or hygiene problem. Object names the offending
+// object, e.g. "deployment/web (container app)" or "node/ip-10-0-1-2".
+// MonthlyImpact estimates the dollars at stake; 0 when none can be put on it.
+type Finding struct {
+	Rule          string
+	Severity      Severity
+	Namespace     string
+	Object        string
+	Message       string
+	MonthlyImpact float64
+}
+
+// findingsOptions is a validated config.FindingsConfig.
+type findingsOptions struct {
+	overRequestFactor float64
+	lowUtilization    float64
+	pendingAfter      time.Duration
+}
+
+func parseFindings(cfg config.FindingsConfig) (findingsOptions, error) {
+	opts := findingsOptions{
+		overRequestFactor: cfg.OverRequestFactor,
+		lowUtilization:    cfg.LowUtilization,
+		pendingAfter:      time.Duration(cfg.PendingMinutes) * time.Minute,
+	}
+	if opts.overRequestFactor < 1 {
+		return findingsOptions{}, fmt.Errorf("findings.over_request_factor must be at least 1, got %g", cfg.OverRequestFactor)
+	}
+	if opts.lowUtilization < 0 || opts.lowUtilization > 1 {
+		return findingsOptions{}, fmt.Errorf("findings.low_utilization must be between 0 and 1, got %g", cfg.LowUtilization)
+	}
+	if cfg.PendingMinutes < 0 {
+		return findingsOptions{}, fmt.Errorf("findings.pending_minutes must not be negative")
+	}
+	return opts, nil
+}
+
+// severityFor grades a finding by its monthly impact, never below floor.
+func severityFor(impact float64, floor Severity) Severity {
+	s := SeverityLow
+	switch {
+	case impact >= findingImpactHigh:
+		s = SeverityHigh
+	case impact >= findingImpactMedium:
+		s = SeverityMedium
+	}
+	if s < floor {
+		return floor
+	}
+	return s
+}
+
+// findings runs every rule over the inventory and its report. Over-requested
+// containers and the impact of containers without requests need
+// inv.ContainerUsage; without it those rules are skipped or report no impact.
+// Findings are ordered by severity, then impact.
+func (c *Calculator) findings(inv Inventory, report *Report, now time.Time) []Finding {
+	var out []Finding
+	out = append(out, c.containerFindings(inv)...)
+	out = append(out, c.overRequestFindings(inv)...)
+	out = append(out, c.pendingFindings(inv, now)...)
+	out = append(out, c.nodeFindings(report)...)
+	out = append(out, claimFindings(report)...)
+	out = append(out, emptyNamespaceFindings(inv, report)...)
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Severity != out[j].Severity {
+			return out[i].Severity > out[j].Severity
+		}
+		if out[i].MonthlyImpact != out[j].MonthlyImpact {
+			return out[i].MonthlyImpact > out[j].MonthlyImpact
+		}
+		if out[i].Rule != out[j].Rule {
+			return out[i].Rule < out[j].Rule
+		}
+		return out[i].Object < out[j].Object
+	})
+	return out
+}
+
+// containerFindings flags app containers without requests, once per
+// workload container, and limits set without a matching request. The API
+// server defaults a pod's missing requests to its limits at admission, so
+// the limits rule reads the pod template of the pod's ReplicaSet, Job,
+// StatefulSet or DaemonSet instead; pods without a listed template are not
+// checked for it.
+func (c *Calculator) containerFindings(inv Inventory) []Finding {
+	owners := newOwnerIndex(inv.ReplicaSets, inv.Jobs)
+	templates := newTemplateIndex(inv)
+	type key struct {
+		rule      string
+		workload  WorkloadRef
+		container string
+	}
+	byKey := make(map[key]*Finding)
+	var order []key
+	add := func(k key, floor Severity, message string, impact float64) {
+		f, ok := byKey[k]
+		if !ok {
+			f = &Finding{
+				Rule:      k.rule,
+				Namespace: k.workload.Namespace,
+				Object:    fmt.Sprintf("%s (container %s)", k.workload, k.container),
+				Message:   message,
+			}
+			byKey[k] = f
+			order = append(order, k)
+		}
+		f.MonthlyImpact += impact
+		f.Severity = severityFor(f.MonthlyImpact, floor)
+	}
+
+	for _, pod := range inv.Pods {
+		if isTerminated(pod.Status.Phase) {
+			continue
+		}
+		workload := owners.resolve(pod)
+		for _, container := range pod.Spec.Containers {
+			requests := container.Resources.Requests
+			_, hasCPU := requests[corev1.ResourceCPU]
+			_, hasMem := requests[corev1.ResourceMemory]
+			if !hasCPU && !hasMem {
+				var impact float64
+				if u, ok := inv.ContainerUsage[stats.UsageKey{Namespace: pod.Namespace, Pod: pod.Name, Container: container.Name}]; ok {
+					impact = (u.CPU.Avg*c.rates.CPUPerHour + u.Memory.Avg/BytesPerGB*c.rates.MemPerGBHour) * HoursPerMonth
+				}
+				add(key{RuleNoRequests, workload, container.Name}, SeverityMedium,
+					"no CPU or memory requests: the scheduler cannot place it reliably and its usage goes unbilled", impact)
+			}
+			if missing := limitsWithoutRequests(templates.container(pod, container.Name)); len(missing) > 0 {
+				add(key{RuleLimitsNoRequests, workload, container.Name}, SeverityLow,
+					fmt.Sprintf("limits without requests for %v: requests default to the limits", missing), 0)
+			}
+		}
+	}
+
+	out := make([]Finding, 0, len(order))
+	for _, k := range order {
+		out = append(out, *byKey[k])
+	}
+	return out
+}
+
+// templateIndex holds the pod templates of listed ReplicaSets, Jobs,
+// StatefulSets and DaemonSets.
+type templateIndex struct {
+	replicaSets  map[ownerKey]*corev1.PodSpec
+	jobs         map[ownerKey]*corev1.PodSpec
+	statefulSets map[ownerKey]*corev1.PodSpec
+	daemonSets   map[ownerKey]*corev1.PodSpec
+}
+
+func newTemplateIndex(inv Inventory) templateIndex {
+	idx := templateIndex{
+		replicaSets:  make(map[ownerKey]*corev1.PodSpec, len(inv.ReplicaSets)),
+		jobs:         make(map[ownerKey]*corev1.PodSpec, len(inv.Jobs)),
+		statefulSets: make(map[ownerKey]*corev1.PodSpec, len(inv.StatefulSets)),
+		daemonSets:   make(map[ownerKey]*corev1.PodSpec, len(inv.DaemonSets)),
+	}
+	for i := range inv.ReplicaSets {
+		rs := &inv.ReplicaSets[i]
+		idx.replicaSets[ownerKey{rs.Namespace, rs.Name}] = &rs.Spec.Template.Spec
+	}
+	for i := range inv.Jobs {
+		job := &inv.Jobs[i]
+		idx.jobs[ownerKey{job.Namespace, job.Name}] = &job.Spec.Template.Spec
+	}
+	for i := range inv.StatefulSets {
+		sts := &inv.StatefulSets[i]
+		idx.statefulSets[ownerKey{sts.Namespace, sts.Name}] = &sts.Spec.Template.Spec
+	}
+	for i := range inv.DaemonSets {
+		ds := &inv.DaemonSets[i]
+		idx.daemonSets[ownerKey{ds.Namespace, ds.Name}] = &ds.Spec.Template.Spec
+	}
+	return idx
+}
+
+// container returns the named container of the template pod was created
+// from, or nil when its controller's template was not listed.
+func (idx templateIndex) container(pod corev1.Pod, name string) *corev1.Container {
+	ref := metav1.GetControllerOf(&pod)
+	if ref == nil {
+		return nil
+	}
+	var spec *corev1.PodSpec
+	switch ref.Kind {
+	case "ReplicaSet":
+		spec = idx.replicaSets[ownerKey{pod.Namespace, ref.Name}]
+	case "Job":
+		spec = idx.jobs[ownerKey{pod.Namespace, ref.Name}]
+	case "StatefulSet":
+		spec = idx.statefulSets[ownerKey{pod.Namespace, ref.Name}]
+	case "DaemonSet":
+		spec = idx.daemonSets[ownerKey{pod.Namespace, ref.Name}]
+	}
+	if spec == nil {
+		return nil
+	}
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == name {
+			return &spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+// limitsWithoutRequests lists the CPU and memory limits of container that
+// have no request.
+func limitsWithoutRequests(container *corev1.Container) []string {
+	if container == nil {
+		return nil
+	}
+	var missing []string
+	for _, r := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if _, hasLimit := container.Resources.Limits[r]; !hasLimit {
+			continue
+		}
+		if _, hasRequest := container.Resources.Requests[r]; !hasRequest {
+			missing = append(missing, string(r))
+		}
+	}
+	return missing
+}
+
+// overRequestFindings flags workload containers requesting at least the
+// configured factor over their p95 CPU or max memory. The impact is the
+// rightsizing saving.
+func (c *Calculator) overRequestFindings(inv Inventory) []Finding {
+	if len(inv.ContainerUsage) == 0 {
+		return nil
+	}
+	var out []Finding
+	factor := c.findingsOptions.overRequestFactor
+	for _, r := range c.Recommend(inv, inv.ContainerUsage, c.rightsizeOptions) {
+		if r.MonthlySavings <= 0 {
+			continue
+		}
+		cpu := float64(r.CurrentCPU.MilliValue()) / 1000
+		mem := float64(r.CurrentMemory.Value())
+		overCPU := r.CPUP95 > 0 && cpu >= factor*r.CPUP95
+		overMem := r.MemoryMax > 0 && mem >= factor*r.MemoryMax
+		if !overCPU && !overMem {
+			continue
+		}
+		out = append(out, Finding{
+			Rule:      RuleOverRequested,
+			Severity:  severityFor(r.MonthlySavings, SeverityLow),
+			Namespace: r.Workload.Namespace,
+			Object:    fmt.Sprintf("%s (container %s)", r.Workload, r.Container),
+			Message: fmt.Sprintf("requests %s CPU / %s memory, uses %.0fm p95 / %s max; suggest %s / %s",
+				r.CurrentCPU.String(), r.CurrentMemory.String(),
+				r.CPUP95*1000, formatBytes(r.MemoryMax),
+				r.RecommendedCPU.String(), r.RecommendedMemory.String()),
+			MonthlyImpact: r.MonthlySavings,
+		})
+	}
+	return out
+}
+
+// pendingFindings flags pods Pending for longer than the configured time.
+// The impact is what their requests will cost once scheduled.
+func (c *Calculator) pendingFindings(inv Inventory, now time.Time) []Finding {
+	var out []Finding
+	for _, pod := range inv.Pods {
+		if pod.Status.Phase != corev1.PodPending || pod.CreationTimestamp.IsZero() {
+			continue
+		}
+		age := now.Sub(pod.CreationTimestamp.Time)
+		if age < c.findingsOptions.pendingAfter {
+			continue
+		}
+		requests := podRequests(pod).Effective
+		impact := c.ContainerCost(requests.Cpu(), requests.Memory())
+		reason := "not scheduled"
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse && cond.Reason != "" {
+				reason = cond.Reason
+			}
+		}
+		out = append(out, Finding{
+			Rule:          RulePendingPod,
+			Severity:      severityFor(impact, SeverityMedium),
+			Namespace:     pod.Namespace,
+			Object:        "pod/" + pod.Name,
+			Message:       fmt.Sprintf("pending for %s (%s)", age.Round(time.Minute), reason),
+			MonthlyImpact: impact,
+		})
+	}
+	return out
+}
+
+// nodeFindings flags nodes whose CPU and memory requests are both below the
+// configured share of allocatable. The impact is the node's idle cost.
+func (c *Calculator) nodeFindings(report *Report) []Finding {
+	var out []Finding
+	for _, n := range report.Nodes {
+		if n.CapacityType == CapacityFargate || n.AllocatableCPU.IsZero() || n.AllocatableMemory.IsZero() {
+			continue
+		}
+		cpu := float64(n.RequestedCPU.MilliValue()) / float64(n.AllocatableCPU.MilliValue())
+		mem := float64(n.RequestedMemory.Value()) / float64(n.AllocatableMemory.Value())
+		if math.Max(cpu, mem) >= c.findingsOptions.lowUtilization {
+			continue
+		}
+		out = append(out, Finding{
+			Rule:          RuleLowNodeUtilization,
+			Severity:      severityFor(n.IdleCost, SeverityLow),
+			Object:        "node/" + n.Name,
+			Message:       fmt.Sprintf("requests use %.0f%% of CPU and %.0f%% of memory", cpu*100, mem*100),
+			MonthlyImpact: n.IdleCost,
+		})
+	}
+	return out
+}
+
+// claimFindings flags claims no pod in the report mounts.
+func claimFindings(report *Report) []Finding {
+	var out []Finding
+	for _, v := range report.Volumes {
+		if v.Claim == "" || len(v.Pods) > 0 {
+			continue
+		}
+		out = append(out, Finding{
+			Rule:          RuleOrphanedClaim,
+			Severity:      severityFor(v.Cost, SeverityLow),
+			Namespace:     v.Namespace,
+			Object:        "pvc/" + v.Claim,
+			Message:       fmt.Sprintf("%s %s volume not mounted by any pod", v.Capacity.String(), v.StorageClass),
+			MonthlyImpact: v.Cost,
+		})
+	}
+	return out
+}
+
+// emptyNamespaceFindings flags listed namespaces without a running pod,
+// except the built-in ones. The impact is whatever the namespace still
+// costs, such as storage and load balancers.
+func emptyNamespaceFindings(inv Inventory, report *Report) []Finding {
+	running := make(map[string]bool)
+	for _, pod := range inv.Pods {
+		if pod.Status.Phase == corev1.PodRunning {
+			running[pod.Namespace] = true
+		}
+	}
+	costs := make(map[string]float64, len(report.Namespaces))
+	for _, ns := range report.Namespaces {
+		costs[ns.Name] = ns.Cost
+	}
+
+	var out []Finding
+	for _, ns := range inv.Namespaces {
+		if running[ns.Name] || builtinNamespaces[ns.Name] || ns.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+		out = append(out, Finding{
+			Rule:          RuleEmptyNamespace,
+			Severity:      severityFor(costs[ns.Name], SeverityLow),
+			Namespace:     ns.Name,
+			Object:        "namespace/" + ns.Name,
+			Message:       "no running pods",
+			MonthlyImpact: costs[ns.Name],
+		})
+	}
+	return out
+}
+
+// formatBytes renders a byte count in the largest binary unit below it.
+func formatBytes(b float64) string {
+	units := []string{"B", "Ki", "Mi", "Gi", "Ti"}
+	i := 0
+	for b >= 1024 && i < len(units)-1 {
+		b /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.0f%s", b, units[i])
+}
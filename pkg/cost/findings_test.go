@@ -0,0 +1,159 @@
+package cost
+
+import (
+	"testing"
+	"time"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/stats"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func findingsByRule(findings []Finding) map[string][]Finding {
+	out := make(map[string][]Finding)
+	for _, f := range findings {
+		out[f.Rule] = append(out[f.Rule], f)
+	}
+	return out
+}
+
+func TestFindings(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	running := func(ns, name string, containers ...corev1.Container) corev1.Pod {
+		pod := testPod(ns, name, containers...)
+		pod.Spec.NodeName = "n1"
+		pod.Status.Phase = corev1.PodRunning
+		return pod
+	}
+	// The template sets only a limit; the pod, as the API server returns it,
+	// has the request defaulted to that limit.
+	limitedTemplate := testContainer("app", "", "")
+	limitedTemplate.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}
+	limitedRS := appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "shop", Name: "limited-7d9f", OwnerReferences: controllerRef("Deployment", "limited"),
+	}}
+	limitedRS.Spec.Template.Spec.Containers = []corev1.Container{limitedTemplate}
+	limited := testContainer("app", "", "1Gi")
+	limited.Resources.Limits = limitedTemplate.Resources.Limits
+	limitedPod := running("shop", "limited-7d9f-x2k", limited)
+	limitedPod.OwnerReferences = controllerRef("ReplicaSet", "limited-7d9f")
+	// StatefulSet pods are checked against the StatefulSet's template.
+	limitedSTS := appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "data", Name: "db"}}
+	limitedSTS.Spec.Template.Spec.Containers = []corev1.Container{limitedTemplate}
+	stsPod := running("data", "db-0", limited)
+	stsPod.OwnerReferences = controllerRef("StatefulSet", "db")
+	// A bare pod has no template to check, however its spec looks.
+	bare := running("shop", "bare", limited)
+	pending := testPod("batch", "stuck", testContainer("app", "4", "8Gi"))
+	pending.Status.Phase = corev1.PodPending
+	pending.CreationTimestamp = metav1.NewTime(now.Add(-2 * time.Hour))
+	fresh := testPod("batch", "fresh", testContainer("app", "1", "1Gi"))
+	fresh.Status.Phase = corev1.PodPending
+	fresh.CreationTimestamp = metav1.NewTime(now.Add(-time.Minute))
+
+	node := testNode("n1", "64Gi", nil)
+	node.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("16"),
+		corev1.ResourceMemory: resource.MustParse("64Gi"),
+	}
+	const mib = 1024 * 1024
+
+	report := calc.Calculate(Inventory{
+		Pods: []corev1.Pod{
+			running("shop", "web", testContainer("app", "2", "4Gi")),
+			running("shop", "unrequested", testContainer("app", "", "")),
+			limitedPod,
+			stsPod,
+			bare,
+			pending,
+			fresh,
+		},
+		Nodes:        []corev1.Node{node},
+		ReplicaSets:  []appsv1.ReplicaSet{limitedRS},
+		StatefulSets: []appsv1.StatefulSet{limitedSTS},
+		PersistentVolumeClaims: []corev1.PersistentVolumeClaim{
+			testClaim("old", "leftover", "pv-left", "gp3", "100Gi"),
+		},
+		PersistentVolumes: []corev1.PersistentVolume{testVolume("pv-left", "gp3", "100Gi", corev1.VolumeBound)},
+		Namespaces: []corev1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "old"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		},
+		ContainerUsage: map[stats.UsageKey]stats.Usage{
+			{Namespace: "shop", Pod: "web", Container: "app"}: {
+				CPU:    stats.Aggregate{P95: 0.2, Samples: 10},
+				Memory: stats.Aggregate{Max: 512 * mib, Samples: 10},
+			},
+		},
+		CollectedAt: now,
+	})
+
+	byRule := findingsByRule(report.Findings)
+	expect := func(rule, object string) Finding {
+		t.Helper()
+		for _, f := range byRule[rule] {
+			if f.Object == object {
+				return f
+			}
+		}
+		t.Fatalf("expected %s finding for %s, got %+v", rule, object, byRule[rule])
+		return Finding{}
+	}
+
+	expect(RuleNoRequests, "pod/unrequested (container app)")
+	expect(RuleLimitsNoRequests, "deployment/limited (container app)")
+	expect(RuleLimitsNoRequests, "statefulset/db (container app)")
+	if len(byRule[RuleLimitsNoRequests]) != 2 {
+		t.Fatalf("expected only the templated deployment and statefulset to be flagged, got %+v", byRule[RuleLimitsNoRequests])
+	}
+	if over := expect(RuleOverRequested, "pod/web (container app)"); over.MonthlyImpact <= 0 {
+		t.Fatalf("expected a positive over-request impact, got %+v", over)
+	}
+	if p := expect(RulePendingPod, "pod/stuck"); p.MonthlyImpact <= 0 || p.Severity < SeverityMedium {
+		t.Fatalf("expected a priced, at least medium pending finding, got %+v", p)
+	}
+	if len(byRule[RulePendingPod]) != 1 {
+		t.Fatalf("expected the fresh pending pod to be left out, got %+v", byRule[RulePendingPod])
+	}
+	if n := expect(RuleLowNodeUtilization, "node/n1"); n.MonthlyImpact != report.Nodes[0].IdleCost {
+		t.Fatalf("expected node impact to be its idle cost, got %+v", n)
+	}
+	expect(RuleOrphanedClaim, "pvc/leftover")
+	expect(RuleEmptyNamespace, "namespace/old")
+	if len(byRule[RuleEmptyNamespace]) != 1 {
+		t.Fatalf("expected only old to be flagged empty, got %+v", byRule[RuleEmptyNamespace])
+	}
+
+	for i := 1; i < len(report.Findings); i++ {
+		if report.Findings[i].Severity > report.Findings[i-1].Severity {
+			t.Fatalf("expected findings ordered by severity, got %+v", report.Findings)
+		}
+	}
+}
+
+func TestSeverityFor(t *testing.T) {
+	if got := severityFor(150, SeverityLow); got != SeverityHigh {
+		t.Fatalf("expected high, got %s", got)
+	}
+	if got := severityFor(0, SeverityMedium); got != SeverityMedium {
+		t.Fatalf("expected floor medium, got %s", got)
+	}
+}
+
+func TestParseFindings_Invalid(t *testing.T) {
+	for _, cfg := range []config.FindingsConfig{
+		{OverRequestFactor: 0.5, LowUtilization: 0.3},
+		{OverRequestFactor: 2, LowUtilization: 1.5},
+		{OverRequestFactor: 2, PendingMinutes: -1},
+	} {
+		if _, err := parseFindings(cfg); err == nil {
+			t.Fatalf("expected error for %+v", cfg)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package cost
+
+import "sort"
+
+// ClusterReport is the report of one cluster in a fleet, named by its
+// kubeconfig context and cluster.
+type ClusterReport struct {
+	Context string
+	Cluster string
+	Report  *Report
+}
+
+// FleetNamespaceCost is a namespace's cost within one cluster of a fleet.
+// Namespaces of the same name in different clusters stay separate.
+type FleetNamespaceCost struct {
+	Context string
+	NamespaceCost
+}
+
+// Fleet combines the reports of several clusters. Clusters keeps the order
+// the reports were given in; Namespaces is every cluster's namespace rollup,
+// highest cost first.
+type Fleet struct {
+	Clusters   []ClusterReport
+	Namespaces []FleetNamespaceCost
+
+	Nodes        int
+	Pods         int
+	TotalCost    float64
+	IdleCost     float64
+	PodTotalCost float64
+}
+
+// NewFleet sums clusters into a Fleet.
+func NewFleet(clusters []ClusterReport) *Fleet {
+	fleet := &Fleet{Clusters: clusters}
+	for _, c := range clusters {
+		r := c.Report
+		fleet.Nodes += len(r.Nodes)
+		fleet.Pods += len(r.Pods)
+		fleet.TotalCost += r.TotalCost
+		fleet.IdleCost += r.IdleCost
+		fleet.PodTotalCost += r.PodTotalCost
+		for _, ns := range r.Namespaces {
+			fleet.Namespaces = append(fleet.Namespaces, FleetNamespaceCost{Context: c.Context, NamespaceCost: ns})
+		}
+	}
+	sort.SliceStable(fleet.Namespaces, func(i, j int) bool {
+		a, b := fleet.Namespaces[i], fleet.Namespaces[j]
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		if a.Context != b.Context {
+			return a.Context < b.Context
+		}
+		return a.Name < b.Name
+	})
+	return fleet
+}
@@ -0,0 +1,47 @@
+package cost
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNewFleet(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+	report := func(node string, pods ...corev1.Pod) *Report {
+		for i := range pods {
+			pods[i].Spec.NodeName = node
+		}
+		return calc.Calculate(Inventory{Pods: pods, Nodes: []corev1.Node{testNode(node, "8Gi", nil)}})
+	}
+	prod := report("prod-1",
+		testPod("web", "a", testContainer("app", "1", "1Gi")),
+		testPod("web", "b", testContainer("app", "1", "1Gi")))
+	staging := report("staging-1", testPod("web", "a", testContainer("app", "500m", "512Mi")))
+
+	fleet := NewFleet([]ClusterReport{
+		{Context: "prod", Cluster: "prod", Report: prod},
+		{Context: "staging", Cluster: "staging", Report: staging},
+	})
+
+	if fleet.Nodes != 2 || fleet.Pods != 3 {
+		t.Fatalf("expected 2 nodes and 3 pods, got %d and %d", fleet.Nodes, fleet.Pods)
+	}
+	if !approxEqual(fleet.TotalCost, prod.TotalCost+staging.TotalCost) {
+		t.Fatalf("expected fleet total %.4f, got %.4f", prod.TotalCost+staging.TotalCost, fleet.TotalCost)
+	}
+	if !approxEqual(fleet.PodTotalCost, prod.PodTotalCost+staging.PodTotalCost) {
+		t.Fatalf("expected fleet pod total %.4f, got %.4f", prod.PodTotalCost+staging.PodTotalCost, fleet.PodTotalCost)
+	}
+
+	if len(fleet.Namespaces) != 2 {
+		t.Fatalf("expected web kept separate per cluster, got %+v", fleet.Namespaces)
+	}
+	first, second := fleet.Namespaces[0], fleet.Namespaces[1]
+	if first.Context != "prod" || first.Name != "web" || first.Pods != 2 {
+		t.Fatalf("expected prod/web first with 2 pods, got %s/%s with %d", first.Context, first.Name, first.Pods)
+	}
+	if second.Context != "staging" || second.Cost >= first.Cost {
+		t.Fatalf("expected staging/web second and cheaper, got %s/%s at %.4f", second.Context, second.Name, second.Cost)
+	}
+}
@@ -0,0 +1,257 @@
+package cost
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/newman-bot/kfin/pkg/pricing"
+	"github.com/newman-bot/kfin/pkg/stats"
+)
+
+// Forecasting needs at least forecastMinDays of history, and two weeks of it
+// before weekly seasonality is fitted.
+const (
+	forecastMinDays      = 3
+	forecastSeasonalDays = 14
+	forecastBandZ        = 1.645 // two-sided 90% normal interval
+	// Cap on backfitting rounds; the slope usually settles in a handful.
+	forecastMaxIterations = 100
+	// ScaleTo refuses to multiply the trend by more than this; a usage level
+	// under 1% of the cost it is scaled to is too close to zero to rebase.
+	forecastMaxScale = 100
+)
+
+// ForecastBandPercent is the confidence level of a Projection's band.
+const ForecastBandPercent = 90
+
+// DailyCost is one UTC day of usage cost, as if the day's average usage ran
+// for all 24 hours.
+type DailyCost struct {
+	Day  time.Time
+	Cost float64
+}
+
+// DailyUsageCosts prices daily CPU cores and memory bytes at rates. Days
+// missing from either series are left out.
+func DailyUsageCosts(cpu, mem []stats.DailyPoint, rates pricing.UsageRates) []DailyCost {
+	memByDay := make(map[int64]float64, len(mem))
+	for _, p := range mem {
+		memByDay[p.Day.Unix()] = p.Avg
+	}
+	out := make([]DailyCost, 0, len(cpu))
+	for _, p := range cpu {
+		memBytes, ok := memByDay[p.Day.Unix()]
+		if !ok {
+			continue
+		}
+		hourly := p.Avg*rates.CPUPerHour + memBytes/BytesPerGB*rates.MemPerGBHour
+		out = append(out, DailyCost{Day: p.Day, Cost: hourly * 24})
+	}
+	return out
+}
+
+// Projection is the forecast cost of one calendar month with its confidence
+// band. Observed is the part of Cost already backed by history.
+type Projection struct {
+	Month    time.Time
+	Cost     float64
+	Low      float64
+	High     float64
+	Observed float64
+}
+
+// Forecast is a linear trend, optionally with weekly seasonality, fitted to
+// daily costs and projected to the end of this month and over next month.
+type Forecast struct {
+	Days []DailyCost
+	// Trend in dollars per day, per day.
+	Slope float64
+	// Weekly is set when seasonality was fitted; Seasonal then holds the
+	// additive offset of each weekday, indexed by time.Weekday.
+	Weekly   bool
+	Seasonal [7]float64
+	// Standard deviation of the daily residuals.
+	StdDev float64
+	// Trend's daily cost on the day containing now, without the weekday
+	// offset.
+	Level float64
+
+	EndOfMonth Projection
+	NextMonth  Projection
+}
+
+// ForecastCosts fits days, which must be in order, and projects the month
+// containing now and the one after it. Observed days of this month count at
+// their actual cost; every other day is predicted. With weekly set and at
+// least two weeks of history, a per-weekday offset is fitted on top of the
+// trend. The band covers daily noise and the uncertainty of the trend, not
+// of the seasonal offsets.
+func ForecastCosts(days []DailyCost, now time.Time, weekly bool) (*Forecast, error) {
+	if len(days) < forecastMinDays {
+		return nil, fmt.Errorf("need at least %d days of history to forecast, got %d", forecastMinDays, len(days))
+	}
+	origin := days[0].Day
+	x := make([]float64, len(days))
+	y := make([]float64, len(days))
+	for i, d := range days {
+		x[i] = dayIndex(origin, d.Day)
+		y[i] = d.Cost
+	}
+
+	f := &Forecast{Days: days, Weekly: weekly && len(days) >= forecastSeasonalDays}
+	fit := fitLine(x, y)
+	if f.Weekly {
+		// Backfit: alternate weekday offsets from the trend's residuals and
+		// the trend of the deseasonalized costs until the slope settles,
+		// which converges on the joint least squares fit.
+		adjusted := make([]float64, len(y))
+		for iter := 0; iter < forecastMaxIterations; iter++ {
+			f.Seasonal = weekdayOffsets(days, func(i int) float64 { return y[i] - fit.at(x[i]) })
+			for i, d := range days {
+				adjusted[i] = y[i] - f.Seasonal[d.Day.Weekday()]
+			}
+			prev := fit.slope
+			fit = fitLine(x, adjusted)
+			if math.Abs(fit.slope-prev) < 1e-9 {
+				break
+			}
+		}
+	}
+	f.Slope = fit.slope
+
+	var sse float64
+	for i, d := range days {
+		e := y[i] - fit.at(x[i]) - f.Seasonal[d.Day.Weekday()]
+		sse += e * e
+	}
+	dof := len(days) - 2
+	if f.Weekly {
+		dof -= 6 // seven offsets that sum to zero
+	}
+	f.StdDev = math.Sqrt(sse / float64(dof))
+
+	observed := make(map[int64]float64, len(days))
+	for _, d := range days {
+		observed[d.Day.Unix()] = d.Cost
+	}
+	predict := func(day time.Time) float64 {
+		return math.Max(0, fit.at(dayIndex(origin, day))+f.Seasonal[day.Weekday()])
+	}
+	project := func(month time.Time) Projection {
+		p := Projection{Month: month}
+		var predicted, m, spread float64
+		for day := month; day.Before(month.AddDate(0, 1, 0)); day = day.AddDate(0, 0, 1) {
+			if cost, ok := observed[day.Unix()]; ok {
+				p.Observed += cost
+				continue
+			}
+			predicted += predict(day)
+			m++
+			spread += dayIndex(origin, day) - fit.meanX
+		}
+		// Variance of a sum of m predictions: m days of noise, plus the
+		// intercept's and the slope's uncertainty, which every day shares.
+		band := forecastBandZ * f.StdDev * math.Sqrt(m+m*m/fit.n+spread*spread/fit.sxx)
+		p.Cost = p.Observed + predicted
+		p.Low = p.Observed + math.Max(0, predicted-band)
+		p.High = p.Observed + predicted + band
+		return p
+	}
+
+	today := time.Date(now.UTC().Year(), now.UTC().Month(), now.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	f.Level = fit.at(dayIndex(origin, today))
+	thisMonth := time.Date(now.UTC().Year(), now.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+	f.EndOfMonth = project(thisMonth)
+	f.NextMonth = project(thisMonth.AddDate(0, 1, 0))
+	return f, nil
+}
+
+// ScaleTo rebases the forecast onto monthly, a cost per HoursPerMonth such as
+// Report.TotalCost. Every dollar is scaled by monthly's daily run rate over
+// the trend's Level, so the projections keep the fitted growth and band but
+// measure the same thing as monthly: a flat trend projects a month at
+// monthly, prorated to its number of days. A Level that is not positive or
+// is negligible next to monthly's daily run rate is an error, since scaling
+// it would blow the projections up.
+func (f *Forecast) ScaleTo(monthly float64) (*Forecast, error) {
+	daily := monthly * 24 / HoursPerMonth
+	if f.Level <= 0 || f.Level*forecastMaxScale < daily {
+		return nil, fmt.Errorf("usage trend today ($%.2f/day) is too small next to $%.2f/day to scale it", f.Level, daily)
+	}
+	k := daily / f.Level
+	scaled := *f
+	scaled.Days = make([]DailyCost, len(f.Days))
+	for i, d := range f.Days {
+		scaled.Days[i] = DailyCost{Day: d.Day, Cost: d.Cost * k}
+	}
+	for wd := range scaled.Seasonal {
+		scaled.Seasonal[wd] *= k
+	}
+	scaled.Slope *= k
+	scaled.StdDev *= k
+	scaled.Level *= k
+	scale := func(p Projection) Projection {
+		return Projection{Month: p.Month, Cost: p.Cost * k, Low: p.Low * k, High: p.High * k, Observed: p.Observed * k}
+	}
+	scaled.EndOfMonth = scale(f.EndOfMonth)
+	scaled.NextMonth = scale(f.NextMonth)
+	return &scaled, nil
+}
+
+// dayIndex is the number of whole days from origin to day.
+func dayIndex(origin, day time.Time) float64 {
+	return math.Round(day.Sub(origin).Hours() / 24)
+}
+
+type lineFit struct {
+	intercept, slope float64
+	n, meanX, sxx    float64
+}
+
+func (l lineFit) at(x float64) float64 {
+	return l.intercept + l.slope*x
+}
+
+// fitLine is an ordinary least squares fit of y on x. The x values are
+// distinct days, so sxx is never zero for more than one point.
+func fitLine(x, y []float64) lineFit {
+	n := float64(len(x))
+	var sumX, sumY float64
+	for i := range x {
+		sumX += x[i]
+		sumY += y[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+	var sxx, sxy float64
+	for i := range x {
+		sxx += (x[i] - meanX) * (x[i] - meanX)
+		sxy += (x[i] - meanX) * (y[i] - meanY)
+	}
+	slope := sxy / sxx
+	return lineFit{intercept: meanY - slope*meanX, slope: slope, n: n, meanX: meanX, sxx: sxx}
+}
+
+// weekdayOffsets averages residual(i) per weekday and centers the averages
+// on zero, so the offsets shift costs between weekdays without changing the
+// trend's level.
+func weekdayOffsets(days []DailyCost, residual func(i int) float64) [7]float64 {
+	var sums [7]float64
+	var counts [7]int
+	for i, d := range days {
+		sums[d.Day.Weekday()] += residual(i)
+		counts[d.Day.Weekday()]++
+	}
+	var offsets [7]float64
+	var mean float64
+	for wd := range offsets {
+		if counts[wd] > 0 {
+			offsets[wd] = sums[wd] / float64(counts[wd])
+		}
+		mean += offsets[wd] / 7
+	}
+	for wd := range offsets {
+		offsets[wd] -= mean
+	}
+	return offsets
+}
@@ -0,0 +1,171 @@
+package cost
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/newman-bot/kfin/pkg/pricing"
+	"github.com/newman-bot/kfin/pkg/stats"
+)
+
+func testDays(start time.Time, costs ...float64) []DailyCost {
+	days := make([]DailyCost, len(costs))
+	for i, c := range costs {
+		days[i] = DailyCost{Day: start.AddDate(0, 0, i), Cost: c}
+	}
+	return days
+}
+
+func TestDailyUsageCosts(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	cpu := []stats.DailyPoint{{Day: day, Avg: 2}, {Day: day.AddDate(0, 0, 1), Avg: 3}}
+	mem := []stats.DailyPoint{{Day: day, Avg: 4 * BytesPerGB}}
+
+	got := DailyUsageCosts(cpu, mem, pricing.UsageRates{CPUPerHour: 0.02, MemPerGBHour: 0.005})
+	if len(got) != 1 || !got[0].Day.Equal(day) || !approxEqual(got[0].Cost, (2*0.02+4*0.005)*24) {
+		t.Fatalf("expected one priced day, got %+v", got)
+	}
+}
+
+func TestForecastCosts_LinearTrend(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	costs := make([]float64, 10)
+	for i := range costs {
+		costs[i] = 100 + 2*float64(i)
+	}
+
+	f, err := ForecastCosts(testDays(start, costs...), start.AddDate(0, 0, 9).Add(12*time.Hour), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.Weekly || !approxEqual(f.Slope, 2) || !approxEqual(f.StdDev, 0) {
+		t.Fatalf("expected an exact trend of $2/day without seasonality, got %+v", f)
+	}
+	// March: ten observed days of $100..$118, then 21 predicted days of
+	// $120..$160. April: 30 predicted days of $162..$220.
+	eom, next := f.EndOfMonth, f.NextMonth
+	if !approxEqual(eom.Observed, 1090) || !approxEqual(eom.Cost, 1090+2940) || !approxEqual(eom.Low, eom.Cost) || !approxEqual(eom.High, eom.Cost) {
+		t.Fatalf("unexpected end of month projection %+v", eom)
+	}
+	if !next.Month.Equal(start.AddDate(0, 1, 0)) || !approxEqual(next.Observed, 0) || !approxEqual(next.Cost, 5730) {
+		t.Fatalf("unexpected next month projection %+v", next)
+	}
+}
+
+func TestForecastCosts_WeeklySeasonality(t *testing.T) {
+	start := time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC) // a Monday
+	days := make([]DailyCost, 28)
+	for i := range days {
+		day := start.AddDate(0, 0, i)
+		cost := 100.0
+		if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			cost = 40
+		}
+		// Alternate a dollar of noise so the band has width.
+		cost += float64(i%2*2 - 1)
+		days[i] = DailyCost{Day: day, Cost: cost}
+	}
+	now := start.AddDate(0, 0, 27)
+
+	f, err := ForecastCosts(days, now, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !f.Weekly {
+		t.Fatalf("expected weekly seasonality with four weeks of history")
+	}
+	if dip := f.Seasonal[time.Monday] - f.Seasonal[time.Saturday]; math.Abs(dip-60) > 2 {
+		t.Fatalf("expected weekends about $60 below weekdays, got %.2f", dip)
+	}
+	// March 2026 has 22 weekdays and 9 weekend days.
+	want := 22*100.0 + 9*40.0
+	next := f.NextMonth
+	if math.Abs(next.Cost-want)/want > 0.02 {
+		t.Fatalf("expected next month near $%.2f, got %+v", want, next)
+	}
+	if !(next.Low < next.Cost && next.Cost < next.High) {
+		t.Fatalf("expected a band around the projection, got %+v", next)
+	}
+
+	flat, err := ForecastCosts(days, now, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flat.Weekly || flat.StdDev <= f.StdDev {
+		t.Fatalf("expected a plain trend to fit worse than the seasonal one, got %.2f vs %.2f", flat.StdDev, f.StdDev)
+	}
+}
+
+func TestForecastCosts_TooFewDays(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := ForecastCosts(testDays(start, 10, 12), start, true); err == nil {
+		t.Fatalf("expected error for two days of history")
+	}
+}
+
+func TestForecast_ScaleTo(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 9).Add(12 * time.Hour)
+	const monthly = 730.0 // $24 a day
+
+	// Flat usage: the projections are the monthly figure prorated to each
+	// month's days, whatever usage itself costs.
+	flat, err := ForecastCosts(testDays(start, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10), now, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	scaled, err := flat.ScaleTo(monthly)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !approxEqual(scaled.EndOfMonth.Cost, monthly*31*24/HoursPerMonth) || !approxEqual(scaled.NextMonth.Cost, monthly*30*24/HoursPerMonth) {
+		t.Fatalf("expected March and April at the monthly basis, got %+v and %+v", scaled.EndOfMonth, scaled.NextMonth)
+	}
+
+	// A growing trend keeps its growth relative to today's level.
+	costs := make([]float64, 10)
+	for i := range costs {
+		costs[i] = 100 + 2*float64(i)
+	}
+	f, err := ForecastCosts(testDays(start, costs...), now, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !approxEqual(f.Level, 118) {
+		t.Fatalf("expected today's trend level $118, got %.4f", f.Level)
+	}
+	scaled, err = f.ScaleTo(monthly)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	k := 24.0 / 118
+	if !approxEqual(scaled.Level, 24) || !approxEqual(scaled.NextMonth.Cost, f.NextMonth.Cost*k) || !approxEqual(scaled.Slope, 2*k) {
+		t.Fatalf("expected the trend scaled by %.4f, got %+v", k, scaled)
+	}
+	if scaled.NextMonth.Cost <= monthly*30*24/HoursPerMonth {
+		t.Fatalf("expected growth above the monthly basis, got %.2f", scaled.NextMonth.Cost)
+	}
+
+	f.Level = 0
+	if _, err := f.ScaleTo(monthly); err == nil {
+		t.Fatalf("expected error scaling a trend at zero")
+	}
+
+	// A trend declining to near zero today would be scaled thousands of
+	// times over; that is refused rather than projected.
+	declining := make([]float64, 10)
+	for i := range declining {
+		declining[i] = 9.01 - float64(i)
+	}
+	f, err = ForecastCosts(testDays(start, declining...), now, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.Level <= 0 || f.Level >= 0.1 {
+		t.Fatalf("expected a tiny positive level, got %.4f", f.Level)
+	}
+	if _, err := f.ScaleTo(monthly); err == nil {
+		t.Fatalf("expected error scaling a negligible level of $%.4f/day to $24/day", f.Level)
+	}
+}
@@ -0,0 +1,187 @@
+package cost
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Group dimensions accepted by ParseGroupBy, besides label:<key>.
+const (
+	GroupNamespace = "namespace"
+	GroupWorkload  = "workload"
+	GroupNode      = "node"
+	GroupLabel     = "label"
+)
+
+// UnlabeledGroup holds cost whose pod and namespace both lack the grouping
+// label. UnallocatedGroup holds cost with no workload or node: namespace-level
+// cost (unmounted claims, load balancers), and unscheduled pods under node.
+const (
+	UnlabeledGroup   = "unlabeled"
+	UnallocatedGroup = "unallocated"
+)
+
+// GroupDimension is one level of a --group-by: a namespace, workload or node,
+// or the value of a pod label, falling back to the namespace's label.
+type GroupDimension struct {
+	Kind  string
+	Label string
+}
+
+func (d GroupDimension) String() string {
+	if d.Kind == GroupLabel {
+		return GroupLabel + ":" + d.Label
+	}
+	return d.Kind
+}
+
+// ParseGroupBy parses a comma-separated list of dimensions such as
+// "label:team,namespace". An empty string means namespace.
+func ParseGroupBy(s string) ([]GroupDimension, error) {
+	if strings.TrimSpace(s) == "" {
+		return []GroupDimension{{Kind: GroupNamespace}}, nil
+	}
+	var dims []GroupDimension
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == GroupNamespace || part == GroupWorkload || part == GroupNode:
+			dims = append(dims, GroupDimension{Kind: part})
+		case strings.HasPrefix(part, GroupLabel+":") && len(part) > len(GroupLabel)+1:
+			dims = append(dims, GroupDimension{Kind: GroupLabel, Label: strings.TrimPrefix(part, GroupLabel+":")})
+		default:
+			return nil, fmt.Errorf("invalid group-by %q (expected: namespace, workload, node or label:<key>, comma-separated)", part)
+		}
+	}
+	return dims, nil
+}
+
+// GroupCost is the cost of one group. Keys holds the group's value for each
+// dimension, in --group-by order.
+type GroupCost struct {
+	Keys []string
+	Pods int
+	Cost float64
+}
+
+// groupItem is a cost that can be placed in a group: a pod, or
+// namespace-level cost that belongs to no pod.
+type groupItem struct {
+	namespace string
+	workload  string
+	node      string
+	labels    map[string]string
+	cost      float64
+	pod       bool
+}
+
+func groupItems(report *Report) []groupItem {
+	var items []groupItem
+	for _, p := range report.Pods {
+		items = append(items, groupItem{
+			namespace: p.Namespace,
+			workload:  p.Namespace + "/" + p.Workload.String(),
+			node:      p.NodeName,
+			labels:    p.Labels,
+			cost:      p.Cost,
+			pod:       true,
+		})
+	}
+	extra := unmountedClaimCosts(report.Volumes)
+	for _, lb := range report.LoadBalancers {
+		extra[lb.Namespace] += lb.Cost
+	}
+	for ns, cost := range extra {
+		items = append(items, groupItem{namespace: ns, workload: UnallocatedGroup, node: UnallocatedGroup, cost: cost})
+	}
+	if report.Allocation == AllocationNodeCost {
+		for _, n := range report.Nodes {
+			if n.IdleCost > 0 {
+				items = append(items, groupItem{namespace: IdleNamespace, workload: IdleNamespace, node: n.Name, cost: n.IdleCost})
+			}
+		}
+	}
+	return items
+}
+
+func groupKey(report *Report, item groupItem, d GroupDimension) string {
+	switch d.Kind {
+	case GroupNamespace:
+		return item.namespace
+	case GroupWorkload:
+		return item.workload
+	case GroupNode:
+		if item.node == "" {
+			return UnallocatedGroup
+		}
+		return item.node
+	}
+	if item.namespace == IdleNamespace {
+		return IdleNamespace
+	}
+	if v, ok := item.labels[d.Label]; ok && v != "" {
+		return v
+	}
+	if v, ok := report.NamespaceLabels[item.namespace][d.Label]; ok && v != "" {
+		return v
+	}
+	return UnlabeledGroup
+}
+
+// GroupCosts rolls the report's pod cost, namespace-level cost and, in
+// node-cost mode, idle cost up by dims, so the groups add up to the same
+// total as Namespaces. Groups are ordered level by level: the costliest
+// first-level group first, then its costliest second-level group, and so on.
+func GroupCosts(report *Report, dims []GroupDimension) []GroupCost {
+	byKey := make(map[string]*GroupCost)
+	// Totals of every key prefix, for ordering.
+	prefixTotals := make(map[string]float64)
+	for _, item := range groupItems(report) {
+		keys := make([]string, len(dims))
+		for i, d := range dims {
+			keys[i] = groupKey(report, item, d)
+			prefixTotals[strings.Join(keys[:i+1], "\x00")] += item.cost
+		}
+		id := strings.Join(keys, "\x00")
+		g, ok := byKey[id]
+		if !ok {
+			g = &GroupCost{Keys: keys}
+			byKey[id] = g
+		}
+		g.Cost += item.cost
+		if item.pod {
+			g.Pods++
+		}
+	}
+
+	out := make([]GroupCost, 0, len(byKey))
+	for _, g := range byKey {
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].Keys, out[j].Keys
+		for l := range a {
+			ta := prefixTotals[strings.Join(a[:l+1], "\x00")]
+			tb := prefixTotals[strings.Join(b[:l+1], "\x00")]
+			if ta != tb {
+				return ta > tb
+			}
+			if a[l] != b[l] {
+				return a[l] < b[l]
+			}
+		}
+		return false
+	})
+	return out
+}
+
+func namespaceLabels(namespaces []corev1.Namespace) map[string]map[string]string {
+	out := make(map[string]map[string]string, len(namespaces))
+	for _, ns := range namespaces {
+		out[ns.Name] = ns.Labels
+	}
+	return out
+}
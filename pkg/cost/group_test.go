@@ -0,0 +1,89 @@
+package cost
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	dims, err := ParseGroupBy("label:team, namespace")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []GroupDimension{{Kind: GroupLabel, Label: "team"}, {Kind: GroupNamespace}}
+	if !reflect.DeepEqual(dims, want) {
+		t.Fatalf("expected %+v, got %+v", want, dims)
+	}
+	if dims, _ := ParseGroupBy(""); len(dims) != 1 || dims[0].Kind != GroupNamespace {
+		t.Fatalf("expected namespace by default, got %+v", dims)
+	}
+	for _, bad := range []string{"team", "label:", "namespace,,node"} {
+		if _, err := ParseGroupBy(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestGroupCosts_Labels(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+	labeled := func(ns, name, team string) corev1.Pod {
+		pod := testPod(ns, name, testContainer("app", "1", "1Gi"))
+		pod.Spec.NodeName = "n1"
+		if team != "" {
+			pod.Labels = map[string]string{"team": team}
+		}
+		return pod
+	}
+	report := calc.Calculate(Inventory{
+		Pods: []corev1.Pod{
+			labeled("shop", "a", "payments"),
+			labeled("shop", "b", "payments"),
+			labeled("search", "c", ""),
+			labeled("batch", "d", ""),
+		},
+		Nodes: []corev1.Node{testNode("n1", "16Gi", nil)},
+		Namespaces: []corev1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{Name: "search", Labels: map[string]string{"team": "discovery"}}},
+		},
+	})
+	podCost := report.Pods[0].Cost
+
+	groups := GroupCosts(report, []GroupDimension{{Kind: GroupLabel, Label: "team"}, {Kind: GroupNamespace}})
+	want := []GroupCost{
+		{Keys: []string{"payments", "shop"}, Pods: 2, Cost: 2 * podCost},
+		{Keys: []string{"discovery", "search"}, Pods: 1, Cost: podCost},
+		{Keys: []string{UnlabeledGroup, "batch"}, Pods: 1, Cost: podCost},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("expected %d groups, got %+v", len(want), groups)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(groups[i].Keys, want[i].Keys) || groups[i].Pods != want[i].Pods || !approxEqual(groups[i].Cost, want[i].Cost) {
+			t.Fatalf("group %d: expected %+v, got %+v", i, want[i], groups[i])
+		}
+	}
+}
+
+func TestGroupCosts_NodeCostIncludesIdle(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.Allocation.Mode = string(AllocationNodeCost)
+	calc := newTestCalculator(t, cfg)
+	pod := testPod("web", "a", testContainer("app", "1", "1Gi"))
+	pod.Spec.NodeName = "n1"
+	report := calc.Calculate(Inventory{
+		Pods:  []corev1.Pod{pod},
+		Nodes: []corev1.Node{testNode("n1", "8Gi", nil)},
+	})
+
+	var total float64
+	for _, g := range GroupCosts(report, []GroupDimension{{Kind: GroupWorkload}}) {
+		total += g.Cost
+	}
+	if !approxEqual(total, report.HardwareCost+report.ElecCost) {
+		t.Fatalf("expected groups to add up to node cost %.4f, got %.4f", report.HardwareCost+report.ElecCost, total)
+	}
+}
@@ -0,0 +1,96 @@
+package cost
+
+import (
+	"fmt"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// nodeMatcher selects nodes by name or label selector, as configured on
+// hardware and power profiles.
+type nodeMatcher struct {
+	nodes    map[string]bool
+	selector labels.Selector
+}
+
+func newNodeMatcher(kind, name string, nodes []string, selector string) (nodeMatcher, error) {
+	if len(nodes) == 0 && selector == "" {
+		return nodeMatcher{}, fmt.Errorf("%s profile %s: set nodes or selector", kind, name)
+	}
+	m := nodeMatcher{nodes: make(map[string]bool, len(nodes))}
+	for _, n := range nodes {
+		m.nodes[n] = true
+	}
+	if selector != "" {
+		sel, err := labels.Parse(selector)
+		if err != nil {
+			return nodeMatcher{}, fmt.Errorf("%s profile %s: invalid selector: %w", kind, name, err)
+		}
+		m.selector = sel
+	}
+	return m, nil
+}
+
+func (m nodeMatcher) matches(node corev1.Node) bool {
+	if m.nodes[node.Name] {
+		return true
+	}
+	return m.selector != nil && m.selector.Matches(labels.Set(node.Labels))
+}
+
+// profileName returns name, or the profile's 1-based position when unnamed.
+func profileName(name string, i int) string {
+	if name == "" {
+		return fmt.Sprintf("#%d", i+1)
+	}
+	return name
+}
+
+// hardwareProfile is a config.HardwareProfile with its node selection parsed.
+type hardwareProfile struct {
+	config.HardwareProfile
+	nodeMatcher
+}
+
+func parseHardwareProfiles(profiles []config.HardwareProfile) ([]hardwareProfile, error) {
+	out := make([]hardwareProfile, 0, len(profiles))
+	for i, p := range profiles {
+		p.Name = profileName(p.Name, i)
+		if p.PurchasePrice > 0 && p.AmortizationMonths <= 0 {
+			return nil, fmt.Errorf("hardware profile %s: purchase_price needs amortization_months", p.Name)
+		}
+		m, err := newNodeMatcher("hardware", p.Name, p.Nodes, p.Selector)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, hardwareProfile{HardwareProfile: p, nodeMatcher: m})
+	}
+	return out, nil
+}
+
+// monthly returns the profile's monthly hardware cost for node.
+func (p hardwareProfile) monthly(node corev1.Node) float64 {
+	if p.PurchasePrice > 0 {
+		return p.PurchasePrice / float64(p.AmortizationMonths)
+	}
+	return capacityCost(node, p.MonthlyPerGB, p.MonthlyPerCore)
+}
+
+// hardwareProfile returns the first profile matching node, or nil.
+func (c *Calculator) hardwareProfile(node corev1.Node) *hardwareProfile {
+	for i := range c.profiles {
+		if c.profiles[i].matches(node) {
+			return &c.profiles[i]
+		}
+	}
+	return nil
+}
+
+// capacityCost prices a node's memory and CPU capacity at monthly rates.
+func capacityCost(node corev1.Node, perGB, perCore float64) float64 {
+	memGB := float64(node.Status.Capacity.Memory().Value()) / BytesPerGB
+	cores := float64(node.Status.Capacity.Cpu().MilliValue()) / 1000.0
+	return memGB*perGB + cores*perCore
+}
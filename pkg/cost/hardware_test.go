@@ -0,0 +1,64 @@
+package cost
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/pricing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func sizedNode(name, cpu, mem string, labels map[string]string) corev1.Node {
+	node := testNode(name, mem, labels)
+	node.Status.Capacity[corev1.ResourceCPU] = resource.MustParse(cpu)
+	return node
+}
+
+func TestNodeHardwareCost_Profiles(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.HardwareMonthlyPerGB = 1
+	cfg.Pricing.HardwareMonthlyPerCore = 0.5
+	cfg.Pricing.InstanceMonthlyByType = map[string]float64{"m5.large": 70}
+	cfg.Pricing.HardwareProfiles = []config.HardwareProfile{
+		{Name: "pi", Selector: "kfin.io/hw=pi", MonthlyPerGB: 0.2, MonthlyPerCore: 0.1},
+		{Name: "nuc", Nodes: []string{"nuc-1"}, PurchasePrice: 720, AmortizationMonths: 36},
+	}
+	calc := newTestCalculator(t, cfg)
+
+	cases := []struct {
+		node    corev1.Node
+		want    float64
+		profile string
+	}{
+		{sizedNode("pi-1", "4", "8Gi", map[string]string{"kfin.io/hw": "pi"}), 8*0.2 + 4*0.1, "pi"},
+		{sizedNode("nuc-1", "8", "32Gi", map[string]string{nodeInstanceTypeLabel: "m5.large"}), 20, "nuc"},
+		{sizedNode("cloud-1", "2", "8Gi", map[string]string{nodeInstanceTypeLabel: "m5.large"}), 70, ""},
+		{sizedNode("other", "2", "4Gi", nil), 4*1 + 2*0.5, ""},
+	}
+	for _, tc := range cases {
+		got, _, _ := calc.NodeHardwareCost(tc.node)
+		if !approxEqual(got, tc.want) {
+			t.Fatalf("%s: expected hardware cost %.4f, got %.4f", tc.node.Name, tc.want, got)
+		}
+		if nc := calc.NodeCost(tc.node, nil); nc.HardwareProfile != tc.profile {
+			t.Fatalf("%s: expected profile %q, got %q", tc.node.Name, tc.profile, nc.HardwareProfile)
+		}
+	}
+}
+
+func TestNewCalculator_InvalidHardwareProfile(t *testing.T) {
+	invalid := [][]config.HardwareProfile{
+		{{Name: "none", MonthlyPerGB: 1}},
+		{{Name: "bad", Selector: "a in (", MonthlyPerGB: 1}},
+		{{Name: "forever", Nodes: []string{"n"}, PurchasePrice: 100}},
+	}
+	for _, profiles := range invalid {
+		cfg := config.DefaultConfig()
+		cfg.Pricing.HardwareProfiles = profiles
+		if _, err := NewCalculator(context.Background(), cfg, pricing.NewStaticProvider(0.02, 0.005)); err == nil {
+			t.Fatalf("expected error for profile %+v", profiles[0])
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package cost
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// isTerminated reports whether a pod in phase has finished and no longer
+// holds its requests on a node.
+func isTerminated(phase corev1.PodPhase) bool {
+	return phase == corev1.PodSucceeded || phase == corev1.PodFailed
+}
+
+// nodeAllocatable returns the node's allocatable resources, falling back to
+// capacity when the kubelet has not reported allocatable.
+func nodeAllocatable(node corev1.Node) corev1.ResourceList {
+	if len(node.Status.Allocatable) > 0 {
+		return node.Status.Allocatable
+	}
+	return node.Status.Capacity
+}
+
+// remaining returns total minus used, clamped at zero for overcommitted nodes.
+func remaining(total, used resource.Quantity) resource.Quantity {
+	out := total.DeepCopy()
+	out.Sub(used)
+	if out.Sign() < 0 {
+		return *resource.NewQuantity(0, total.Format)
+	}
+	return out
+}
+
+func fraction(part, whole int64) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return float64(part) / float64(whole)
+}
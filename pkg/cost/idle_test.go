@@ -0,0 +1,60 @@
+package cost
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestCalculate_IdleCost(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+
+	node := testNode("worker-1", "8Gi", nil)
+	node.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+	}
+	scheduled := testPod("web", "api", testContainer("app", "1", "4Gi"))
+	scheduled.Spec.NodeName = "worker-1"
+	finished := testPod("batch", "done", testContainer("app", "2", "2Gi"))
+	finished.Spec.NodeName = "worker-1"
+	finished.Status.Phase = corev1.PodSucceeded
+	pending := testPod("web", "pending", testContainer("app", "2", "2Gi"))
+
+	report := calc.Calculate(Inventory{
+		Pods:  []corev1.Pod{scheduled, finished, pending},
+		Nodes: []corev1.Node{node},
+	})
+
+	n := report.Nodes[0]
+	if want := resource.MustParse("3"); n.IdleCPU.Cmp(want) != 0 {
+		t.Fatalf("expected 3 idle cores, got %s", n.IdleCPU.String())
+	}
+	if want := resource.MustParse("4Gi"); n.IdleMemory.Cmp(want) != 0 {
+		t.Fatalf("expected 4Gi idle memory, got %s", n.IdleMemory.String())
+	}
+	// 75% cpu idle, 50% memory idle
+	want := n.TotalCost * 0.625
+	if !approxEqual(n.IdleCost, want) || !approxEqual(report.IdleCost, want) {
+		t.Fatalf("expected idle cost %.6f, got node=%.6f cluster=%.6f", want, n.IdleCost, report.IdleCost)
+	}
+}
+
+func TestCalculate_IdleCostOvercommitted(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+
+	node := testNode("worker-1", "1Gi", nil)
+	node.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	pod := testPod("web", "api", testContainer("app", "2", "2Gi"))
+	pod.Spec.NodeName = "worker-1"
+
+	report := calc.Calculate(Inventory{Pods: []corev1.Pod{pod}, Nodes: []corev1.Node{node}})
+
+	if report.IdleCost != 0 || !report.Nodes[0].IdleCPU.IsZero() {
+		t.Fatalf("expected no idle capacity on overcommitted node, got %+v", report.Nodes[0])
+	}
+}
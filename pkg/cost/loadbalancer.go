@@ -0,0 +1,125 @@
+package cost
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// Load balancer types keyed in pricing.load_balancers.per_hour.
+const (
+	LoadBalancerClassic = "classic"
+	LoadBalancerNLB     = "nlb"
+	LoadBalancerALB     = "alb"
+)
+
+const (
+	awsLoadBalancerTypeAnnotation = "service.beta.kubernetes.io/aws-load-balancer-type"
+	awsLoadBalancerClassNLB       = "service.k8s.aws/nlb"
+	ingressClassAnnotation        = "kubernetes.io/ingress.class"
+	albGroupAnnotation            = "alb.ingress.kubernetes.io/group.name"
+)
+
+// LoadBalancerCost is the monthly cost of one cloud load balancer. Kind is
+// Service or Ingress; an ALB shared by an ingress group is split evenly
+// across its ingresses, one entry each.
+type LoadBalancerCost struct {
+	Name      string
+	Namespace string
+	Kind      string
+	Type      string
+	Cost      float64
+}
+
+// LoadBalancerRate returns the hourly rate for a load balancer type, falling
+// back to pricing.load_balancers.default_per_hour.
+func (c *Calculator) LoadBalancerRate(lbType string) float64 {
+	if rate, ok := c.cfg.Pricing.LoadBalancers.PerHour[lbType]; ok {
+		return rate
+	}
+	return c.cfg.Pricing.LoadBalancers.DefaultPerHour
+}
+
+// serviceLoadBalancerType classifies a LoadBalancer Service. Off EKS the
+// type is left empty and priced at the default rate.
+func serviceLoadBalancerType(svc corev1.Service, eks bool) string {
+	if svc.Spec.LoadBalancerClass != nil && *svc.Spec.LoadBalancerClass == awsLoadBalancerClassNLB {
+		return LoadBalancerNLB
+	}
+	switch strings.ToLower(svc.Annotations[awsLoadBalancerTypeAnnotation]) {
+	case "nlb", "nlb-ip", "external":
+		return LoadBalancerNLB
+	}
+	if eks {
+		return LoadBalancerClassic
+	}
+	return ""
+}
+
+// isALBIngress reports whether the AWS Load Balancer Controller provisions an
+// ALB for ing.
+func isALBIngress(ing networkingv1.Ingress) bool {
+	if ing.Spec.IngressClassName != nil && *ing.Spec.IngressClassName == LoadBalancerALB {
+		return true
+	}
+	return ing.Annotations[ingressClassAnnotation] == LoadBalancerALB
+}
+
+// priceLoadBalancers prices every LoadBalancer Service and, when
+// pricing.load_balancers.include_ingress is set, every ALB behind an Ingress.
+func (c *Calculator) priceLoadBalancers(report *Report, services []corev1.Service, ingresses []networkingv1.Ingress, eks bool) {
+	for _, svc := range services {
+		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			continue
+		}
+		lbType := serviceLoadBalancerType(svc, eks)
+		report.LoadBalancers = append(report.LoadBalancers, LoadBalancerCost{
+			Name:      svc.Name,
+			Namespace: svc.Namespace,
+			Kind:      "Service",
+			Type:      lbType,
+			Cost:      HoursPerMonth * c.LoadBalancerRate(lbType),
+		})
+	}
+
+	if c.cfg.Pricing.LoadBalancers.IncludeIngress {
+		// Ingresses in the same group share one ALB.
+		groups := make(map[string][]networkingv1.Ingress)
+		var order []string
+		for _, ing := range ingresses {
+			if !isALBIngress(ing) {
+				continue
+			}
+			key := ing.Namespace + "/" + ing.Name
+			if group := ing.Annotations[albGroupAnnotation]; group != "" {
+				key = group
+			}
+			if _, ok := groups[key]; !ok {
+				order = append(order, key)
+			}
+			groups[key] = append(groups[key], ing)
+		}
+		monthly := HoursPerMonth * c.LoadBalancerRate(LoadBalancerALB)
+		for _, key := range order {
+			members := groups[key]
+			for _, ing := range members {
+				report.LoadBalancers = append(report.LoadBalancers, LoadBalancerCost{
+					Name:      ing.Name,
+					Namespace: ing.Namespace,
+					Kind:      "Ingress",
+					Type:      LoadBalancerALB,
+					Cost:      monthly / float64(len(members)),
+				})
+			}
+		}
+	}
+
+	for _, lb := range report.LoadBalancers {
+		report.LoadBalancerCost += lb.Cost
+	}
+	sort.SliceStable(report.LoadBalancers, func(i, j int) bool {
+		return report.LoadBalancers[i].Cost > report.LoadBalancers[j].Cost
+	})
+}
@@ -0,0 +1,77 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testService(namespace, name string, svcType corev1.ServiceType, annotations map[string]string) corev1.Service {
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations},
+		Spec:       corev1.ServiceSpec{Type: svcType},
+	}
+}
+
+func testIngress(namespace, name string, annotations map[string]string) networkingv1.Ingress {
+	return networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations}}
+}
+
+func TestCalculate_LoadBalancerCosts(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.LoadBalancers.PerHour = map[string]float64{"classic": 0.025, "nlb": 0.02, "alb": 0.03}
+	cfg.Pricing.LoadBalancers.IncludeIngress = true
+	calc := newTestCalculator(t, cfg)
+
+	eksNode := testNode("worker-1", "8Gi", map[string]string{"eks.amazonaws.com/nodegroup": "ng"})
+	report := calc.Calculate(Inventory{
+		Nodes: []corev1.Node{eksNode},
+		Services: []corev1.Service{
+			testService("web", "frontend", corev1.ServiceTypeLoadBalancer, nil),
+			testService("web", "grpc", corev1.ServiceTypeLoadBalancer, map[string]string{awsLoadBalancerTypeAnnotation: "nlb"}),
+			testService("web", "internal", corev1.ServiceTypeClusterIP, nil),
+		},
+		Ingresses: []networkingv1.Ingress{
+			testIngress("shop", "cart", map[string]string{ingressClassAnnotation: "alb", albGroupAnnotation: "public"}),
+			testIngress("blog", "posts", map[string]string{ingressClassAnnotation: "alb", albGroupAnnotation: "public"}),
+			testIngress("blog", "nginx", map[string]string{ingressClassAnnotation: "nginx"}),
+		},
+	})
+
+	if len(report.LoadBalancers) != 4 {
+		t.Fatalf("expected 4 load balancer entries, got %+v", report.LoadBalancers)
+	}
+	want := HoursPerMonth * (0.025 + 0.02 + 0.03)
+	if !approxEqual(report.LoadBalancerCost, want) {
+		t.Fatalf("expected load balancer cost %.4f, got %.4f", want, report.LoadBalancerCost)
+	}
+
+	byNS := map[string]float64{}
+	for _, ns := range report.Namespaces {
+		byNS[ns.Name] = ns.LoadBalancerCost
+	}
+	if !approxEqual(byNS["shop"], HoursPerMonth*0.015) || !approxEqual(byNS["blog"], HoursPerMonth*0.015) {
+		t.Fatalf("expected shared alb split across namespaces, got %+v", byNS)
+	}
+	if !approxEqual(byNS["web"], HoursPerMonth*0.045) {
+		t.Fatalf("expected classic + nlb on web, got %.4f", byNS["web"])
+	}
+}
+
+func TestServiceLoadBalancerType(t *testing.T) {
+	plain := testService("web", "lb", corev1.ServiceTypeLoadBalancer, nil)
+	if got := serviceLoadBalancerType(plain, true); got != LoadBalancerClassic {
+		t.Fatalf("expected classic on eks, got %q", got)
+	}
+	if got := serviceLoadBalancerType(plain, false); got != "" {
+		t.Fatalf("expected unknown type off eks, got %q", got)
+	}
+	class := awsLoadBalancerClassNLB
+	plain.Spec.LoadBalancerClass = &class
+	if got := serviceLoadBalancerType(plain, true); got != LoadBalancerNLB {
+		t.Fatalf("expected nlb from load balancer class, got %q", got)
+	}
+}
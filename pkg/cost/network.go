@@ -0,0 +1,172 @@
+package cost
+
+import (
+	"sort"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/stats"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const zoneLabel = "topology.kubernetes.io/zone"
+
+const secondsPerMonth = HoursPerMonth * 3600
+
+// NetworkTraffic is projected monthly traffic in GB, split by how it is
+// billed.
+type NetworkTraffic struct {
+	InternetGB  float64
+	CrossZoneGB float64
+	InZoneGB    float64
+}
+
+func (t *NetworkTraffic) add(o NetworkTraffic) {
+	t.InternetGB += o.InternetGB
+	t.CrossZoneGB += o.CrossZoneGB
+	t.InZoneGB += o.InZoneGB
+}
+
+// NetworkCost is the monthly network cost of the pods in one namespace.
+type NetworkCost struct {
+	Namespace string
+	Pods      int
+	Traffic   NetworkTraffic
+	Cost      float64
+}
+
+// zoneIndex estimates cross-zone traffic from how nodes are spread over
+// topology.kubernetes.io/zone.
+type zoneIndex struct {
+	nodeZone  map[string]string
+	zoneNodes map[string]int
+	total     int
+}
+
+func newZoneIndex(nodes []corev1.Node) zoneIndex {
+	idx := zoneIndex{nodeZone: make(map[string]string), zoneNodes: make(map[string]int)}
+	for _, node := range nodes {
+		zone := node.Labels[zoneLabel]
+		if zone == "" {
+			continue
+		}
+		idx.nodeZone[node.Name] = zone
+		idx.zoneNodes[zone]++
+		idx.total++
+	}
+	return idx
+}
+
+// crossZoneShare is the share of in-cluster traffic from a pod on nodeName
+// expected to leave its zone, assuming peers are spread like the nodes. It is
+// zero when the node has no zone label or the cluster spans one zone.
+func (z zoneIndex) crossZoneShare(nodeName string) float64 {
+	zone, ok := z.nodeZone[nodeName]
+	if !ok || len(z.zoneNodes) < 2 {
+		return 0
+	}
+	return 1 - float64(z.zoneNodes[zone])/float64(z.total)
+}
+
+// PodNetwork is the projected monthly network traffic and cost of one pod.
+type PodNetwork struct {
+	Traffic NetworkTraffic
+	Cost    float64
+}
+
+// podTraffic projects a pod's average transmit rate to a month and splits it
+// into internet egress (internet_fraction) and in-cluster traffic, which is
+// divided into cross-zone and in-zone by the zone spread.
+func podTraffic(rates config.NetworkPricing, bytesPerSecond float64, crossZoneShare float64) NetworkTraffic {
+	monthlyGB := bytesPerSecond * secondsPerMonth / BytesPerGB
+	internet := monthlyGB * rates.InternetFraction
+	cluster := monthlyGB - internet
+	return NetworkTraffic{
+		InternetGB:  internet,
+		CrossZoneGB: cluster * crossZoneShare,
+		InZoneGB:    cluster * (1 - crossZoneShare),
+	}
+}
+
+// TrafficCost prices traffic at the pricing.network rates.
+func TrafficCost(rates config.NetworkPricing, t NetworkTraffic) float64 {
+	return t.InternetGB*rates.InternetEgressPerGB + t.CrossZoneGB*rates.CrossZonePerGB + t.InZoneGB*rates.InZonePerGB
+}
+
+// NetworkCosts prices each pod's transmit rate in tx at rates and rolls the
+// cost up per namespace. nodeOf maps pods to the node used for the cross-zone
+// estimate; pods missing from it are priced as in-zone cluster traffic since
+// their node is unknown.
+func NetworkCosts(rates config.NetworkPricing, nodes []corev1.Node, nodeOf map[stats.PodKey]string, tx map[stats.PodKey]stats.Aggregate) (map[stats.PodKey]PodNetwork, []NetworkCost) {
+	zones := newZoneIndex(nodes)
+	pods := make(map[stats.PodKey]PodNetwork, len(tx))
+	byNS := make(map[string]*NetworkCost)
+	for key, agg := range tx {
+		traffic := podTraffic(rates, agg.Avg, zones.crossZoneShare(nodeOf[key]))
+		pn := PodNetwork{Traffic: traffic, Cost: TrafficCost(rates, traffic)}
+		pods[key] = pn
+
+		item, ok := byNS[key.Namespace]
+		if !ok {
+			item = &NetworkCost{Namespace: key.Namespace}
+			byNS[key.Namespace] = item
+		}
+		item.Pods++
+		item.Traffic.add(pn.Traffic)
+		item.Cost += pn.Cost
+	}
+	return pods, sortedNetworkCosts(byNS)
+}
+
+// PodNodes maps each pod to the node it is scheduled on, for NetworkCosts.
+func PodNodes(pods []corev1.Pod) map[stats.PodKey]string {
+	nodeOf := make(map[stats.PodKey]string, len(pods))
+	for _, pod := range pods {
+		nodeOf[stats.PodKey{Namespace: pod.Namespace, Name: pod.Name}] = pod.Spec.NodeName
+	}
+	return nodeOf
+}
+
+// priceNetwork adds each billed pod's projected network cost to its Cost and
+// rolls it up per namespace.
+func (c *Calculator) priceNetwork(report *Report, nodes []corev1.Node, tx map[stats.PodKey]stats.Aggregate) {
+	if len(tx) == 0 {
+		return
+	}
+	billed := make(map[stats.PodKey]stats.Aggregate, len(report.Pods))
+	nodeOf := make(map[stats.PodKey]string, len(report.Pods))
+	for _, pod := range report.Pods {
+		key := stats.PodKey{Namespace: pod.Namespace, Name: pod.Name}
+		if agg, ok := tx[key]; ok {
+			billed[key] = agg
+			nodeOf[key] = pod.NodeName
+		}
+	}
+
+	perPod, byNS := NetworkCosts(c.cfg.Pricing.Network, nodes, nodeOf, billed)
+	for i := range report.Pods {
+		pod := &report.Pods[i]
+		pn, ok := perPod[stats.PodKey{Namespace: pod.Namespace, Name: pod.Name}]
+		if !ok {
+			continue
+		}
+		pod.Network = pn.Traffic
+		pod.NetworkCost = pn.Cost
+		pod.Cost += pod.NetworkCost
+		report.NetworkCost += pod.NetworkCost
+	}
+	report.Network = byNS
+}
+
+func sortedNetworkCosts(byNS map[string]*NetworkCost) []NetworkCost {
+	out := make([]NetworkCost, 0, len(byNS))
+	for _, item := range byNS {
+		out = append(out, *item)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Cost == out[j].Cost {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Cost > out[j].Cost
+	})
+	return out
+}
@@ -0,0 +1,91 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/stats"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCalculate_NetworkCosts(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.Network.InternetEgressPerGB = 0.09
+	cfg.Pricing.Network.CrossZonePerGB = 0.02
+	cfg.Pricing.Network.InZonePerGB = 0
+	cfg.Pricing.Network.InternetFraction = 0.5
+	calc := newTestCalculator(t, cfg)
+
+	nodes := []corev1.Node{
+		testNode("a-1", "8Gi", map[string]string{zoneLabel: "us-east-2a"}),
+		testNode("b-1", "8Gi", map[string]string{zoneLabel: "us-east-2b"}),
+		testNode("b-2", "8Gi", map[string]string{zoneLabel: "us-east-2b"}),
+		testNode("c-1", "8Gi", map[string]string{zoneLabel: "us-east-2c"}),
+	}
+	api := testPod("web", "api", testContainer("app", "", ""))
+	api.Spec.NodeName = "a-1"
+	idle := testPod("web", "quiet", testContainer("app", "", ""))
+	idle.Spec.NodeName = "b-1"
+
+	// 100 GB per month.
+	rate := 100.0 * BytesPerGB / secondsPerMonth
+	report := calc.Calculate(Inventory{
+		Pods:  []corev1.Pod{api, idle},
+		Nodes: nodes,
+		NetworkTransmit: map[stats.PodKey]stats.Aggregate{
+			{Namespace: "web", Name: "api"}: {Avg: rate},
+		},
+	})
+
+	// Half leaves the cluster; a-1 holds a quarter of the nodes, so three
+	// quarters of the remaining 50 GB crosses zones.
+	pod := report.Pods[0]
+	want := NetworkTraffic{InternetGB: 50, CrossZoneGB: 37.5, InZoneGB: 12.5}
+	if !approxEqual(pod.Network.InternetGB, want.InternetGB) ||
+		!approxEqual(pod.Network.CrossZoneGB, want.CrossZoneGB) ||
+		!approxEqual(pod.Network.InZoneGB, want.InZoneGB) {
+		t.Fatalf("expected traffic %+v, got %+v", want, pod.Network)
+	}
+	wantCost := 50*0.09 + 37.5*0.02
+	if !approxEqual(pod.NetworkCost, wantCost) || !approxEqual(report.NetworkCost, wantCost) {
+		t.Fatalf("expected network cost %.4f, got pod %.4f report %.4f", wantCost, pod.NetworkCost, report.NetworkCost)
+	}
+	if report.Pods[1].NetworkCost != 0 {
+		t.Fatalf("expected no network cost without samples, got %.4f", report.Pods[1].NetworkCost)
+	}
+	if len(report.Network) != 1 || report.Network[0].Namespace != "web" || report.Network[0].Pods != 1 {
+		t.Fatalf("unexpected namespace network rollup %+v", report.Network)
+	}
+}
+
+func TestZoneIndex_SingleZone(t *testing.T) {
+	zones := newZoneIndex([]corev1.Node{
+		testNode("a-1", "8Gi", map[string]string{zoneLabel: "us-east-2a"}),
+		testNode("a-2", "8Gi", map[string]string{zoneLabel: "us-east-2a"}),
+	})
+	if share := zones.crossZoneShare("a-1"); share != 0 {
+		t.Fatalf("expected no cross-zone traffic in a single zone, got %f", share)
+	}
+	if share := zones.crossZoneShare("unknown"); share != 0 {
+		t.Fatalf("expected no cross-zone traffic for unlabeled node, got %f", share)
+	}
+}
+
+func TestNetworkCosts_UnknownNodeIsInZone(t *testing.T) {
+	rates := config.NetworkPricing{InternetEgressPerGB: 0.09, CrossZonePerGB: 0.02, InternetFraction: 0.5}
+	nodes := []corev1.Node{
+		testNode("a-1", "8Gi", map[string]string{zoneLabel: "us-east-2a"}),
+		testNode("b-1", "8Gi", map[string]string{zoneLabel: "us-east-2b"}),
+	}
+	rate := 100.0 * BytesPerGB / secondsPerMonth
+	key := stats.PodKey{Namespace: "web", Name: "gone"}
+
+	perPod, byNS := NetworkCosts(rates, nodes, nil, map[stats.PodKey]stats.Aggregate{key: {Avg: rate}})
+	want := NetworkTraffic{InternetGB: 50, InZoneGB: 50}
+	if got := perPod[key].Traffic; !approxEqual(got.InternetGB, want.InternetGB) || got.CrossZoneGB != 0 || !approxEqual(got.InZoneGB, want.InZoneGB) {
+		t.Fatalf("expected traffic %+v for a pod without a node, got %+v", want, got)
+	}
+	if len(byNS) != 1 || !approxEqual(byNS[0].Cost, 50*0.09) {
+		t.Fatalf("unexpected namespace rollup %+v", byNS)
+	}
+}
@@ -0,0 +1,89 @@
+package cost
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Namespace annotations carrying ownership metadata.
+const (
+	AnnotationOwner      = "kfin.io/owner"
+	AnnotationCostCenter = "kfin.io/cost-center"
+	// Monthly budget in dollars, e.g. "500" or "$1,200.50".
+	AnnotationBudget = "kfin.io/budget"
+)
+
+// annotateNamespaces copies owner, cost center and budget annotations from
+// the listed namespaces onto the rollup.
+func annotateNamespaces(rollup []NamespaceCost, namespaces []corev1.Namespace) {
+	byName := make(map[string]map[string]string, len(namespaces))
+	for _, ns := range namespaces {
+		byName[ns.Name] = ns.Annotations
+	}
+	for i := range rollup {
+		annotations := byName[rollup[i].Name]
+		rollup[i].Owner = strings.TrimSpace(annotations[AnnotationOwner])
+		rollup[i].CostCenter = strings.TrimSpace(annotations[AnnotationCostCenter])
+		rollup[i].Budget, _ = ParseBudget(annotations[AnnotationBudget])
+	}
+}
+
+// namespaceOwnership merges the annotated rollup with the listed namespaces,
+// so a namespace without running pods still shows up at zero cost, in name
+// order after the rollup.
+func namespaceOwnership(rollup []NamespaceCost, namespaces []corev1.Namespace) []NamespaceCost {
+	out := make([]NamespaceCost, 0, len(namespaces))
+	seen := make(map[string]bool, len(rollup))
+	for _, ns := range rollup {
+		if ns.Name == IdleNamespace {
+			continue
+		}
+		seen[ns.Name] = true
+		out = append(out, ns)
+	}
+	var missing []NamespaceCost
+	for _, ns := range namespaces {
+		if !seen[ns.Name] {
+			missing = append(missing, NamespaceCost{Name: ns.Name})
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Name < missing[j].Name })
+	annotateNamespaces(missing, namespaces)
+	return append(out, missing...)
+}
+
+// ParseBudget parses a dollar amount such as "500", "$1,200.50" or
+// "1200/month". It returns false for empty, malformed, negative, NaN or
+// infinite values.
+func ParseBudget(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(s, "/month")
+	s = strings.TrimPrefix(strings.TrimSpace(s), "$")
+	s = strings.ReplaceAll(s, ",", "")
+	if s == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+// UnownedNamespaces returns the report's namespaces without a kfin.io/owner
+// annotation, including those with no pods, highest cost first.
+func UnownedNamespaces(report *Report) []NamespaceCost {
+	var out []NamespaceCost
+	for _, ns := range report.NamespaceOwnership {
+		if ns.Owner != "" {
+			continue
+		}
+		out = append(out, ns)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Cost > out[j].Cost })
+	return out
+}
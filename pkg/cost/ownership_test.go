@@ -0,0 +1,80 @@
+package cost
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseBudget(t *testing.T) {
+	cases := map[string]float64{"500": 500, "$1,200.50": 1200.5, " 300/month": 300}
+	for in, want := range cases {
+		got, ok := ParseBudget(in)
+		if !ok || got != want {
+			t.Fatalf("%q: expected %v, got %v (ok=%v)", in, want, got, ok)
+		}
+	}
+	for _, bad := range []string{"", "lots", "-5", "NaN", "Inf", "+Inf", "-Inf"} {
+		if _, ok := ParseBudget(bad); ok {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestNamespaceAnnotations(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+	owned := testPod("shop", "a", testContainer("app", "1", "1Gi"))
+	legacy := testPod("legacy-batch", "b", testContainer("app", "2", "2Gi"))
+	report := calc.Calculate(Inventory{
+		Pods: []corev1.Pod{owned, legacy},
+		Namespaces: []corev1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{Name: "shop", Annotations: map[string]string{
+				AnnotationOwner:      "payments-team",
+				AnnotationCostCenter: "CC-1042",
+				AnnotationBudget:     "$250",
+			}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "legacy-batch", Annotations: map[string]string{AnnotationBudget: "n/a"}}},
+		},
+	})
+
+	byName := make(map[string]NamespaceCost)
+	for _, ns := range report.Namespaces {
+		byName[ns.Name] = ns
+	}
+	if shop := byName["shop"]; shop.Owner != "payments-team" || shop.CostCenter != "CC-1042" || shop.Budget != 250 {
+		t.Fatalf("expected shop annotations on the rollup, got %+v", shop)
+	}
+	if batch := byName["legacy-batch"]; batch.Owner != "" || batch.Budget != 0 {
+		t.Fatalf("expected legacy-batch to be unowned without a budget, got %+v", batch)
+	}
+
+	unowned := UnownedNamespaces(report)
+	if len(unowned) != 1 || unowned[0].Name != "legacy-batch" {
+		t.Fatalf("expected only legacy-batch to be unowned, got %+v", unowned)
+	}
+}
+
+func TestUnownedNamespaces_WithoutPods(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+	finished := testPod("legacy-batch", "cron-1", testContainer("app", "1", "1Gi"))
+	finished.Status.Phase = corev1.PodSucceeded
+	report := calc.Calculate(Inventory{
+		Pods: []corev1.Pod{testPod("shop", "a", testContainer("app", "1", "1Gi")), finished},
+		Namespaces: []corev1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{Name: "shop", Annotations: map[string]string{AnnotationOwner: "payments-team"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "legacy-batch", Annotations: map[string]string{AnnotationCostCenter: "CC-7"}}},
+		},
+	})
+
+	if len(report.NamespaceOwnership) != 2 {
+		t.Fatalf("expected both listed namespaces, got %+v", report.NamespaceOwnership)
+	}
+	unowned := UnownedNamespaces(report)
+	if len(unowned) != 1 || unowned[0].Name != "legacy-batch" {
+		t.Fatalf("expected legacy-batch to be unowned, got %+v", unowned)
+	}
+	if batch := unowned[0]; batch.Pods != 0 || batch.Cost != 0 || batch.CostCenter != "CC-7" {
+		t.Fatalf("expected legacy-batch at zero cost with its cost center, got %+v", batch)
+	}
+}
@@ -0,0 +1,124 @@
+package cost
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Power sources reported in NodeCost.PowerSource.
+const (
+	PowerStatic   = "static"
+	PowerModel    = "model"
+	PowerMeasured = "measured"
+)
+
+// NodePower holds Prometheus-derived power inputs for one node.
+type NodePower struct {
+	// Average busy share of the node's CPUs, 0..1.
+	Utilization    float64
+	HasUtilization bool
+	// Average measured draw in watts; zero when not measured.
+	MeasuredWatts float64
+}
+
+// PowerSourceNeedsUtilization reports whether a pricing.power.source value
+// needs node CPU utilization from Prometheus.
+func PowerSourceNeedsUtilization(source string) bool {
+	return strings.EqualFold(strings.TrimSpace(source), "utilization")
+}
+
+// PowerSourceIsMeasured reports whether a pricing.power.source value reads
+// measured node power from Prometheus.
+func PowerSourceIsMeasured(source string) bool {
+	switch strings.ToLower(strings.TrimSpace(source)) {
+	case "kepler", "hwmon":
+		return true
+	}
+	return false
+}
+
+func validatePowerSource(source string) error {
+	switch strings.ToLower(strings.TrimSpace(source)) {
+	case "", "static", "utilization", "kepler", "hwmon":
+		return nil
+	}
+	return fmt.Errorf("invalid pricing.power.source %q (expected: static, utilization, kepler or hwmon)", source)
+}
+
+// validatePUE rejects a PUE below 1, which would price power below the
+// draw it is derived from. Zero means unset.
+func validatePUE(pue float64) error {
+	if pue == 0 {
+		return nil
+	}
+	if pue < 1 || math.IsNaN(pue) || math.IsInf(pue, 0) {
+		return fmt.Errorf("pricing.power.pue must be at least 1, got %g", pue)
+	}
+	return nil
+}
+
+// powerProfile is a config.PowerProfile with its node selection parsed.
+type powerProfile struct {
+	config.PowerProfile
+	nodeMatcher
+}
+
+func parsePowerProfiles(profiles []config.PowerProfile) ([]powerProfile, error) {
+	out := make([]powerProfile, 0, len(profiles))
+	for i, p := range profiles {
+		p.Name = profileName(p.Name, i)
+		if p.IdleWatts < 0 || p.MaxWatts < p.IdleWatts {
+			return nil, fmt.Errorf("power profile %s: need 0 <= idle_watts <= max_watts", p.Name)
+		}
+		m, err := newNodeMatcher("power", p.Name, p.Nodes, p.Selector)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, powerProfile{PowerProfile: p, nodeMatcher: m})
+	}
+	return out, nil
+}
+
+// NodeWatts returns the node's average draw in watts before PUE and where
+// it came from. Measured power wins; otherwise a matching power profile is
+// interpolated between idle and max by CPU utilization, assuming full load
+// when utilization is unknown; other nodes draw pricing.watts_per_node.
+func (c *Calculator) NodeWatts(node corev1.Node, power map[string]NodePower) (float64, string) {
+	p := power[node.Name]
+	if p.MeasuredWatts > 0 {
+		return p.MeasuredWatts, PowerMeasured
+	}
+	for _, profile := range c.powerProfiles {
+		if !profile.matches(node) {
+			continue
+		}
+		util := 1.0
+		if p.HasUtilization {
+			util = clamp01(p.Utilization)
+		}
+		return profile.IdleWatts + (profile.MaxWatts-profile.IdleWatts)*util, PowerModel
+	}
+	return c.cfg.Pricing.WattsPerNode, PowerStatic
+}
+
+// pue returns the configured PUE, treating unset values as 1.
+func (c *Calculator) pue() float64 {
+	if c.cfg.Pricing.Power.PUE == 0 {
+		return 1
+	}
+	return c.cfg.Pricing.Power.PUE
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
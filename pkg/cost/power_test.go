@@ -0,0 +1,82 @@
+package cost
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/pricing"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNodeWatts(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.WattsPerNode = 15
+	cfg.Pricing.ElectricityRate = 0.10
+	cfg.Pricing.Power.PUE = 1.5
+	cfg.Pricing.Power.Profiles = []config.PowerProfile{
+		{Name: "nuc", Selector: "kfin.io/hw=nuc", IdleWatts: 10, MaxWatts: 60},
+	}
+	calc := newTestCalculator(t, cfg)
+
+	nuc := testNode("nuc-1", "32Gi", map[string]string{"kfin.io/hw": "nuc"})
+	pi := testNode("pi-1", "8Gi", nil)
+	metered := testNode("nuc-2", "32Gi", map[string]string{"kfin.io/hw": "nuc"})
+	power := map[string]NodePower{
+		"nuc-1": {Utilization: 0.4, HasUtilization: true},
+		"nuc-2": {Utilization: 0.4, HasUtilization: true, MeasuredWatts: 42},
+	}
+
+	cases := []struct {
+		node   corev1.Node
+		watts  float64
+		source string
+	}{
+		{nuc, 30, PowerModel},
+		{pi, 15, PowerStatic},
+		{metered, 42, PowerMeasured},
+	}
+	for _, tc := range cases {
+		watts, source := calc.NodeWatts(tc.node, power)
+		if !approxEqual(watts, tc.watts) || source != tc.source {
+			t.Fatalf("%s: expected %.1fW %s, got %.1fW %s", tc.node.Name, tc.watts, tc.source, watts, source)
+		}
+		want := tc.watts * 1.5 / 1000 * HoursPerMonth * 0.10
+		if got := calc.NodeElectricityCost(tc.node, power); !approxEqual(got, want) {
+			t.Fatalf("%s: expected electricity $%.4f, got $%.4f", tc.node.Name, want, got)
+		}
+	}
+
+	// Without utilization samples the profile assumes full load.
+	if watts, _ := calc.NodeWatts(nuc, nil); !approxEqual(watts, 60) {
+		t.Fatalf("expected max watts without utilization, got %.1f", watts)
+	}
+
+	_, elec, _ := calc.ClusterCosts([]corev1.Node{nuc, pi, metered}, power)
+	if want := (30 + 15 + 42) * 1.5 / 1000 * HoursPerMonth * 0.10; !approxEqual(elec, want) {
+		t.Fatalf("expected cluster electricity $%.4f, got $%.4f", want, elec)
+	}
+}
+
+func TestNewCalculator_InvalidPowerConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.Power.Source = "smart-plug"
+	if _, err := NewCalculator(context.Background(), cfg, pricing.NewStaticProvider(0.02, 0.005)); err == nil {
+		t.Fatalf("expected error for invalid power source")
+	}
+
+	cfg = config.DefaultConfig()
+	cfg.Pricing.Power.Profiles = []config.PowerProfile{{Name: "pi", Nodes: []string{"pi-1"}, IdleWatts: 8, MaxWatts: 5}}
+	if _, err := NewCalculator(context.Background(), cfg, pricing.NewStaticProvider(0.02, 0.005)); err == nil {
+		t.Fatalf("expected error for max_watts below idle_watts")
+	}
+
+	for _, pue := range []float64{0.8, -1, math.NaN(), math.Inf(1)} {
+		cfg = config.DefaultConfig()
+		cfg.Pricing.Power.PUE = pue
+		if _, err := NewCalculator(context.Background(), cfg, pricing.NewStaticProvider(0.02, 0.005)); err == nil {
+			t.Fatalf("expected error for pue %g", pue)
+		}
+	}
+}
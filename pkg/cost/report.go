@@ -0,0 +1,174 @@
+package cost
+
+import (
+	"github.com/newman-bot/kfin/pkg/pricing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Report is the renderer-neutral result of a cost calculation. The analyze
+// text output, the TUI dashboard and the PDF export all render from it.
+type Report struct {
+	Pods       []PodCost
+	Workloads  []WorkloadCost
+	Namespaces []NamespaceCost
+	Nodes      []NodeCost
+	Volumes    []VolumeCost
+	// LoadBalancers lists priced load balancers, highest cost first.
+	LoadBalancers []LoadBalancerCost
+	// Network is projected network cost per namespace, highest first.
+	Network []NetworkCost
+
+	HardwareCost     float64
+	ElecCost         float64
+	ControlPlaneCost float64
+	// Persistent volume cost, including StorageWasteCost.
+	StorageCost float64
+	// Cost of volumes no claim can use: Available, Released or Failed.
+	StorageWasteCost float64
+	// Cloud load balancers provisioned for Services and Ingresses.
+	LoadBalancerCost float64
+	// Projected internet egress, cross-zone and in-zone traffic.
+	NetworkCost float64
+	// EKS Fargate pods, billed per pod rather than through node cost.
+	FargateCost float64
+	FargatePods int
+	TotalCost   float64
+	// Share of node hardware and electricity cost not reserved by any pod
+	// request. It is part of TotalCost, not in addition to it.
+	IdleCost float64
+
+	// Nodes on spot capacity, their share of the InstanceNodes (every node
+	// but Fargate's virtual ones) and the monthly hardware saving against
+	// on-demand prices.
+	SpotNodes     int
+	InstanceNodes int
+	SpotShare     float64
+	SpotSavings   float64
+
+	// Labels of each namespace, for label grouping of pods that lack the
+	// label themselves. Empty when namespaces could not be listed.
+	NamespaceLabels map[string]map[string]string
+
+	// Every namespace with its ownership annotations: the rollup without the
+	// idle bucket, then listed namespaces with no pods at zero cost.
+	NamespaceOwnership []NamespaceCost
+
+	// Tenant invoice lines under pricing.chargeback; nil when disabled.
+	Chargeback *Chargeback
+
+	// Waste and hygiene problems, most severe first.
+	Findings []Finding
+
+	// Budgets from config and kfin.io/budget annotations, highest burn
+	// first.
+	Budgets []BudgetResult
+
+	// Extended resources billed to at least one pod, sorted by name.
+	ExtendedResources []corev1.ResourceName
+
+	// Sum of billed quantities and cost across Pods.
+	RequestedCPU    resource.Quantity
+	RequestedMemory resource.Quantity
+	PodTotalCost    float64
+
+	// Distribution detected from node metadata; it picks the control plane
+	// price in ControlPlaneCost.
+	Distribution  Distribution
+	PricingSource string
+	Rates         pricing.UsageRates
+	CostBasis     CostBasis
+	Allocation    AllocationMode
+	// Share of node cost attributed to CPU versus memory, as ratio:1.
+	CPUMemoryRatio float64
+	// Pods billed on requests because a usage-based basis had no samples.
+	UsageMissing int
+	// Succeeded and Failed pods left out of Pods.
+	TerminatedExcluded int
+	// Whether pod cost is prorated by runtime within Period, and whether
+	// running pods are projected to the end of Period rather than billed up
+	// to the collection time.
+	Prorated  bool
+	Projected bool
+	Period    Period
+}
+
+// PodCost is the monthly cost of a single pod. CPU and Memory are the
+// quantities billed under Basis. Cost prices them at the usage rates, or with
+// node-cost allocation, is the pod's share of its node. Containers lists each
+// container's requests at the usage rates and does not necessarily sum to
+// Cost. Extended holds the priced extended resources the pod requests, and
+// ExtendedCost their share of Cost. RuntimeFraction is the share of the
+// billing period the pod runs for and is 1 unless the report is prorated.
+type PodCost struct {
+	Name       string
+	Namespace  string
+	NodeName   string
+	Phase      corev1.PodPhase
+	Workload   WorkloadRef
+	Labels     map[string]string
+	Requests   PodRequests
+	Basis      CostBasis
+	Containers []ContainerCost
+	CPU        resource.Quantity
+	Memory     resource.Quantity
+	Cost       float64
+
+	Extended     corev1.ResourceList
+	ExtendedCost float64
+
+	// PersistentVolumeClaims the pod mounts, and its even share of their
+	// cost, included in Cost.
+	Claims      []string
+	StorageCost float64
+
+	// Projected monthly traffic and its cost, included in Cost.
+	Network     NetworkTraffic
+	NetworkCost float64
+
+	RuntimeFraction float64
+	// Fargate pods are billed at pricing.eks.fargate rates on their
+	// rounded-up size, held in CPU and Memory, in every allocation mode.
+	Fargate bool
+}
+
+// ContainerCost is the monthly cost of one container's resource requests.
+type ContainerCost struct {
+	Name   string
+	Kind   string
+	CPU    resource.Quantity
+	Memory resource.Quantity
+	Cost   float64
+}
+
+// NodeCost is the monthly hardware and electricity cost of a node, along with
+// how much of its allocatable capacity is left unrequested.
+type NodeCost struct {
+	Name             string
+	InstanceType     string
+	InstanceOverride bool
+	// Name of the pricing.hardware_profiles entry that priced the node.
+	HardwareProfile string
+	MemoryGB        float64
+	HardwareCost    float64
+	ElecCost        float64
+	TotalCost       float64
+	// CapacityType is spot, on-demand or unknown (""). OnDemandCost is the
+	// hardware cost at on-demand prices, equal to HardwareCost unless the
+	// node runs on discounted spot capacity.
+	CapacityType string
+	Zone         string
+	OnDemandCost float64
+	// Average draw in watts before PUE and whether it was measured, modelled
+	// from a power profile or the static watts_per_node.
+	Watts       float64
+	PowerSource string
+
+	AllocatableCPU    resource.Quantity
+	AllocatableMemory resource.Quantity
+	RequestedCPU      resource.Quantity
+	RequestedMemory   resource.Quantity
+	IdleCPU           resource.Quantity
+	IdleMemory        resource.Quantity
+	IdleCost          float64
+}
@@ -0,0 +1,89 @@
+package cost
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Container kinds recorded on ContainerCost.
+const (
+	ContainerKindApp     = "app"
+	ContainerKindInit    = "init"
+	ContainerKindSidecar = "sidecar"
+)
+
+// PodRequests breaks a pod's effective request down the way the scheduler
+// computes it: the larger of the app phase (containers plus sidecars) and the
+// init phase peak, plus RuntimeClass overhead.
+type PodRequests struct {
+	Containers corev1.ResourceList
+	Sidecars   corev1.ResourceList
+	InitPeak   corev1.ResourceList
+	Overhead   corev1.ResourceList
+	Effective  corev1.ResourceList
+}
+
+// isSidecar reports whether an init container is a native sidecar, i.e. a
+// restartable init container that keeps running alongside the app containers.
+func isSidecar(c corev1.Container) bool {
+	return c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+func podRequests(pod corev1.Pod) PodRequests {
+	return podResources(pod, func(c corev1.Container) corev1.ResourceList { return c.Resources.Requests })
+}
+
+// podResources applies the scheduler's pod sizing rules to the per-container
+// quantities returned by pick.
+func podResources(pod corev1.Pod, pick func(corev1.Container) corev1.ResourceList) PodRequests {
+	req := PodRequests{
+		Containers: corev1.ResourceList{},
+		Sidecars:   corev1.ResourceList{},
+		InitPeak:   corev1.ResourceList{},
+		Overhead:   corev1.ResourceList{},
+	}
+
+	for _, c := range pod.Spec.Containers {
+		addResources(req.Containers, pick(c))
+	}
+
+	// Sidecars started before an init container keep running while it does,
+	// so each init step is charged together with the sidecars that precede it.
+	running := corev1.ResourceList{}
+	for _, c := range pod.Spec.InitContainers {
+		step := corev1.ResourceList{}
+		if isSidecar(c) {
+			addResources(req.Sidecars, pick(c))
+			addResources(running, pick(c))
+			addResources(step, running)
+		} else {
+			addResources(step, running)
+			addResources(step, pick(c))
+		}
+		maxResources(req.InitPeak, step)
+	}
+
+	addResources(req.Overhead, pod.Spec.Overhead)
+
+	req.Effective = corev1.ResourceList{}
+	addResources(req.Effective, req.Containers)
+	addResources(req.Effective, req.Sidecars)
+	maxResources(req.Effective, req.InitPeak)
+	addResources(req.Effective, req.Overhead)
+	return req
+}
+
+func addResources(dst, src corev1.ResourceList) {
+	for name, q := range src {
+		cur := dst[name]
+		cur.Add(q)
+		dst[name] = cur
+	}
+}
+
+func maxResources(dst, src corev1.ResourceList) {
+	for name, q := range src {
+		if cur, ok := dst[name]; !ok || q.Cmp(cur) > 0 {
+			dst[name] = q.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package cost
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func sidecarContainer(name, cpu, mem string) corev1.Container {
+	c := testContainer(name, cpu, mem)
+	always := corev1.ContainerRestartPolicyAlways
+	c.RestartPolicy = &always
+	return c
+}
+
+func TestPodRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		pod     corev1.Pod
+		wantCPU string
+		wantMem string
+	}{
+		{
+			name:    "app containers only",
+			pod:     testPod("ns", "p", testContainer("a", "100m", "64Mi"), testContainer("b", "200m", "64Mi")),
+			wantCPU: "300m",
+			wantMem: "128Mi",
+		},
+		{
+			name: "init container larger than app",
+			pod: func() corev1.Pod {
+				p := testPod("ns", "p", testContainer("app", "100m", "64Mi"))
+				p.Spec.InitContainers = []corev1.Container{testContainer("migrate", "1", "32Mi")}
+				return p
+			}(),
+			wantCPU: "1",
+			wantMem: "64Mi",
+		},
+		{
+			name: "sidecar adds to app and to later init steps",
+			pod: func() corev1.Pod {
+				p := testPod("ns", "p", testContainer("app", "500m", "256Mi"))
+				p.Spec.InitContainers = []corev1.Container{
+					sidecarContainer("istio-proxy", "100m", "128Mi"),
+					testContainer("setup", "600m", "64Mi"),
+				}
+				return p
+			}(),
+			// app phase: 600m/384Mi, init peak: 700m/192Mi
+			wantCPU: "700m",
+			wantMem: "384Mi",
+		},
+		{
+			name: "runtime class overhead",
+			pod: func() corev1.Pod {
+				p := testPod("ns", "p", testContainer("app", "250m", "128Mi"))
+				p.Spec.Overhead = corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("250m"),
+					corev1.ResourceMemory: resource.MustParse("160Mi"),
+				}
+				return p
+			}(),
+			wantCPU: "500m",
+			wantMem: "288Mi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := podRequests(tt.pod)
+			wantCPU := resource.MustParse(tt.wantCPU)
+			wantMem := resource.MustParse(tt.wantMem)
+			if got := req.Effective.Cpu(); got.Cmp(wantCPU) != 0 {
+				t.Fatalf("expected cpu %s, got %s", wantCPU.String(), got.String())
+			}
+			if got := req.Effective.Memory(); got.Cmp(wantMem) != 0 {
+				t.Fatalf("expected memory %s, got %s", wantMem.String(), got.String())
+			}
+		})
+	}
+}
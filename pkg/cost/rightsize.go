@@ -0,0 +1,198 @@
+package cost
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/stats"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// RightsizeOptions controls how usage turns into recommended requests.
+type RightsizeOptions struct {
+	// Fraction added on top of p95 CPU and max memory, e.g. 0.2 for 20%.
+	Headroom float64
+	// Floors for recommended requests.
+	MinCPU    resource.Quantity
+	MinMemory resource.Quantity
+}
+
+// ParseRightsizeOptions validates the recommend section of the config.
+func ParseRightsizeOptions(cfg config.RecommendConfig) (RightsizeOptions, error) {
+	if cfg.Headroom < 0 || math.IsNaN(cfg.Headroom) || math.IsInf(cfg.Headroom, 0) {
+		return RightsizeOptions{}, fmt.Errorf("recommend.headroom must be a non-negative fraction, got %g", cfg.Headroom)
+	}
+	opts := RightsizeOptions{Headroom: cfg.Headroom}
+	var err error
+	if opts.MinCPU, err = parseFloor("recommend.min_cpu", cfg.MinCPU); err != nil {
+		return RightsizeOptions{}, err
+	}
+	if opts.MinMemory, err = parseFloor("recommend.min_memory", cfg.MinMemory); err != nil {
+		return RightsizeOptions{}, err
+	}
+	return opts, nil
+}
+
+func parseFloor(field, s string) (resource.Quantity, error) {
+	if s == "" {
+		return resource.Quantity{}, nil
+	}
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("%s: %w", field, err)
+	}
+	if q.Sign() < 0 {
+		return resource.Quantity{}, fmt.Errorf("%s must not be negative", field)
+	}
+	return q, nil
+}
+
+// Recommendation is a suggested request change for one container of a
+// workload. Current requests, p95 CPU and max memory are the highest across
+// the workload's replicas, so the recommendation fits the busiest one.
+type Recommendation struct {
+	Workload  WorkloadRef
+	Container string
+	// ContainerKindApp, or ContainerKindSidecar for a restartable init
+	// container.
+	Kind     string
+	Replicas int
+
+	CurrentCPU    resource.Quantity
+	CurrentMemory resource.Quantity
+	// Lowest CPU and memory limits across the replicas; zero when unset.
+	LimitCPU    resource.Quantity
+	LimitMemory resource.Quantity
+	// Observed p95 CPU in cores and max working set in bytes.
+	CPUP95    float64
+	MemoryMax float64
+	Samples   int
+
+	RecommendedCPU    resource.Quantity
+	RecommendedMemory resource.Quantity
+	// Set when the recommendation was lowered to the container's limit,
+	// since the API server rejects requests above limits. The container then
+	// needs a higher limit to get the headroom.
+	CPUAtLimit    bool
+	MemoryAtLimit bool
+	// Monthly cost of the current minus the recommended requests across all
+	// replicas, priced like the report's containers. Negative when the
+	// container needs more than it requests.
+	MonthlySavings float64
+}
+
+type rightsizeKey struct {
+	workload  WorkloadRef
+	container string
+}
+
+// Recommend compares the requests of each running app and sidecar container
+// with its usage, keyed at stats.LevelContainer, and suggests requests of
+// p95 CPU and max memory plus headroom, capped at the container's limits.
+// Containers without usage samples, and those whose requests already match,
+// are left out. Recommendations are ordered by savings, highest first.
+func (c *Calculator) Recommend(inv Inventory, usage map[stats.UsageKey]stats.Usage, opts RightsizeOptions) []Recommendation {
+	owners := newOwnerIndex(inv.ReplicaSets, inv.Jobs)
+	byKey := make(map[rightsizeKey]*Recommendation)
+	var order []rightsizeKey
+	observe := func(pod corev1.Pod, workload WorkloadRef, container corev1.Container, kind string) {
+		u, ok := usage[stats.UsageKey{Namespace: pod.Namespace, Pod: pod.Name, Container: container.Name}]
+		if !ok || u.CPU.Samples == 0 || u.Memory.Samples == 0 {
+			return
+		}
+		key := rightsizeKey{workload, container.Name}
+		rec, ok := byKey[key]
+		if !ok {
+			rec = &Recommendation{Workload: workload, Container: container.Name, Kind: kind}
+			byKey[key] = rec
+			order = append(order, key)
+		}
+		rec.Replicas++
+		rec.Samples += u.CPU.Samples
+		rec.CPUP95 = math.Max(rec.CPUP95, u.CPU.P95)
+		rec.MemoryMax = math.Max(rec.MemoryMax, u.Memory.Max)
+		if cpu := container.Resources.Requests[corev1.ResourceCPU]; cpu.Cmp(rec.CurrentCPU) > 0 {
+			rec.CurrentCPU = cpu
+		}
+		if mem := container.Resources.Requests[corev1.ResourceMemory]; mem.Cmp(rec.CurrentMemory) > 0 {
+			rec.CurrentMemory = mem
+		}
+		lowerLimit(&rec.LimitCPU, container.Resources.Limits, corev1.ResourceCPU)
+		lowerLimit(&rec.LimitMemory, container.Resources.Limits, corev1.ResourceMemory)
+	}
+	for _, pod := range inv.Pods {
+		if pod.Spec.NodeName == "" || isTerminated(pod.Status.Phase) {
+			continue
+		}
+		workload := owners.resolve(pod)
+		for _, container := range pod.Spec.Containers {
+			observe(pod, workload, container, ContainerKindApp)
+		}
+		for _, container := range pod.Spec.InitContainers {
+			if isSidecar(container) {
+				observe(pod, workload, container, ContainerKindSidecar)
+			}
+		}
+	}
+
+	out := make([]Recommendation, 0, len(order))
+	for _, key := range order {
+		rec := byKey[key]
+		rec.RecommendedCPU, rec.CPUAtLimit = capAtLimit(recommendCPU(rec.CPUP95, opts), rec.LimitCPU)
+		rec.RecommendedMemory, rec.MemoryAtLimit = capAtLimit(recommendMemory(rec.MemoryMax, opts), rec.LimitMemory)
+		if rec.RecommendedCPU.Cmp(rec.CurrentCPU) == 0 && rec.RecommendedMemory.Cmp(rec.CurrentMemory) == 0 {
+			continue
+		}
+		current := c.ContainerCost(&rec.CurrentCPU, &rec.CurrentMemory)
+		recommended := c.ContainerCost(&rec.RecommendedCPU, &rec.RecommendedMemory)
+		rec.MonthlySavings = (current - recommended) * float64(rec.Replicas)
+		out = append(out, *rec)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].MonthlySavings > out[j].MonthlySavings })
+	return out
+}
+
+// lowerLimit keeps the lowest limit set for name across replicas in dst.
+func lowerLimit(dst *resource.Quantity, limits corev1.ResourceList, name corev1.ResourceName) {
+	limit, ok := limits[name]
+	if !ok || limit.IsZero() {
+		return
+	}
+	if dst.IsZero() || limit.Cmp(*dst) < 0 {
+		*dst = limit.DeepCopy()
+	}
+}
+
+// capAtLimit returns q, or limit when a limit is set and q exceeds it.
+func capAtLimit(q, limit resource.Quantity) (resource.Quantity, bool) {
+	if !limit.IsZero() && q.Cmp(limit) > 0 {
+		return limit.DeepCopy(), true
+	}
+	return q, false
+}
+
+// recommendCPU is p95 cores plus headroom, rounded up to a whole millicore
+// and no lower than MinCPU.
+func recommendCPU(p95 float64, opts RightsizeOptions) resource.Quantity {
+	milli := int64(math.Ceil(p95 * (1 + opts.Headroom) * 1000))
+	q := *resource.NewMilliQuantity(milli, resource.DecimalSI)
+	if q.Cmp(opts.MinCPU) < 0 {
+		return opts.MinCPU.DeepCopy()
+	}
+	return q
+}
+
+// recommendMemory is max bytes plus headroom, rounded up to a whole MiB and
+// no lower than MinMemory.
+func recommendMemory(max float64, opts RightsizeOptions) resource.Quantity {
+	const mib = 1024 * 1024
+	mibs := int64(math.Ceil(max * (1 + opts.Headroom) / mib))
+	q := *resource.NewQuantity(mibs*mib, resource.BinarySI)
+	if q.Cmp(opts.MinMemory) < 0 {
+		return opts.MinMemory.DeepCopy()
+	}
+	return q
+}
@@ -0,0 +1,138 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/stats"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestRecommend(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+	opts, err := ParseRightsizeOptions(config.DefaultConfig().Recommend)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	replica := func(name string) corev1.Pod {
+		pod := testPod("shop", name, testContainer("app", "2", "4Gi"), testContainer("idle", "10m", "32Mi"))
+		pod.Spec.NodeName = "n1"
+		pod.Labels = map[string]string{"pod-template-hash": "abc12"}
+		pod.OwnerReferences = controllerRef("ReplicaSet", "web-abc12")
+		return pod
+	}
+	const mib = 1024 * 1024
+	usage := map[stats.UsageKey]stats.Usage{
+		{Namespace: "shop", Pod: "web-1", Container: "app"}: {
+			CPU:    stats.Aggregate{P95: 0.4, Samples: 10},
+			Memory: stats.Aggregate{Max: 900 * mib, Samples: 10},
+		},
+		{Namespace: "shop", Pod: "web-2", Container: "app"}: {
+			CPU:    stats.Aggregate{P95: 0.5, Samples: 10},
+			Memory: stats.Aggregate{Max: 1000 * mib, Samples: 10},
+		},
+		// Already at the floors: no recommendation.
+		{Namespace: "shop", Pod: "web-1", Container: "idle"}: {
+			CPU:    stats.Aggregate{P95: 0.001, Samples: 10},
+			Memory: stats.Aggregate{Max: 1 * mib, Samples: 10},
+		},
+	}
+
+	recs := calc.Recommend(Inventory{Pods: []corev1.Pod{replica("web-1"), replica("web-2")}}, usage, opts)
+	if len(recs) != 1 {
+		t.Fatalf("expected one recommendation, got %+v", recs)
+	}
+	r := recs[0]
+	if r.Workload.Kind != "Deployment" || r.Workload.Name != "web" || r.Container != "app" || r.Replicas != 2 {
+		t.Fatalf("expected deployment/web app with 2 replicas, got %+v", r)
+	}
+	if got := r.RecommendedCPU.String(); got != "600m" {
+		t.Fatalf("expected busiest p95 plus 20%% headroom (600m), got %s", got)
+	}
+	if got := r.RecommendedMemory.String(); got != "1200Mi" {
+		t.Fatalf("expected max memory plus 20%% headroom (1200Mi), got %s", got)
+	}
+	perReplica := (1.4*0.02 + (4-1200.0/1024)*0.005) * HoursPerMonth
+	if !approxEqual(r.MonthlySavings, 2*perReplica) {
+		t.Fatalf("expected savings %.4f, got %.4f", 2*perReplica, r.MonthlySavings)
+	}
+}
+
+func TestRecommend_UnderRequested(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+	opts, _ := ParseRightsizeOptions(config.RecommendConfig{})
+	pod := testPod("batch", "worker", testContainer("app", "100m", ""))
+	pod.Spec.NodeName = "n1"
+	usage := map[stats.UsageKey]stats.Usage{
+		{Namespace: "batch", Pod: "worker", Container: "app"}: {
+			CPU:    stats.Aggregate{P95: 1, Samples: 5},
+			Memory: stats.Aggregate{Max: 512 * 1024 * 1024, Samples: 5},
+		},
+	}
+	recs := calc.Recommend(Inventory{Pods: []corev1.Pod{pod}}, usage, opts)
+	if len(recs) != 1 || recs[0].MonthlySavings >= 0 {
+		t.Fatalf("expected a cost increase for an under-requested container, got %+v", recs)
+	}
+}
+
+func TestRecommend_SidecarsAndLimits(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+	opts, _ := ParseRightsizeOptions(config.DefaultConfig().Recommend)
+
+	always := corev1.ContainerRestartPolicyAlways
+	proxy := testContainer("proxy", "1", "1Gi")
+	proxy.RestartPolicy = &always
+	app := testContainer("app", "100m", "128Mi")
+	app.Resources.Limits = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+	pod := testPod("shop", "web", app)
+	pod.Spec.InitContainers = []corev1.Container{proxy}
+	pod.Spec.NodeName = "n1"
+
+	const mib = 1024 * 1024
+	usage := map[stats.UsageKey]stats.Usage{
+		{Namespace: "shop", Pod: "web", Container: "proxy"}: {
+			CPU:    stats.Aggregate{P95: 0.05, Samples: 10},
+			Memory: stats.Aggregate{Max: 64 * mib, Samples: 10},
+		},
+		// Busier than its limits allow with headroom.
+		{Namespace: "shop", Pod: "web", Container: "app"}: {
+			CPU:    stats.Aggregate{P95: 0.45, Samples: 10},
+			Memory: stats.Aggregate{Max: 240 * mib, Samples: 10},
+		},
+	}
+
+	recs := calc.Recommend(Inventory{Pods: []corev1.Pod{pod}}, usage, opts)
+	byName := make(map[string]Recommendation)
+	for _, r := range recs {
+		byName[r.Container] = r
+	}
+
+	sidecar, ok := byName["proxy"]
+	if !ok || sidecar.Kind != ContainerKindSidecar || sidecar.RecommendedCPU.String() != "60m" {
+		t.Fatalf("expected a sidecar recommendation of 60m, got %+v", sidecar)
+	}
+	current, recommended := resource.MustParse("1"), resource.MustParse("1Gi")
+	want := calc.ContainerCost(&current, &recommended) - calc.ContainerCost(&sidecar.RecommendedCPU, &sidecar.RecommendedMemory)
+	if !approxEqual(sidecar.MonthlySavings, want) {
+		t.Fatalf("expected savings priced like report containers (%.4f), got %.4f", want, sidecar.MonthlySavings)
+	}
+
+	capped := byName["app"]
+	if capped.RecommendedCPU.String() != "500m" || capped.RecommendedMemory.String() != "256Mi" || !capped.CPUAtLimit || !capped.MemoryAtLimit {
+		t.Fatalf("expected requests capped at the 500m/256Mi limits, got %+v", capped)
+	}
+}
+
+func TestParseRightsizeOptions_Invalid(t *testing.T) {
+	if _, err := ParseRightsizeOptions(config.RecommendConfig{Headroom: -0.1}); err == nil {
+		t.Fatalf("expected error for negative headroom")
+	}
+	if _, err := ParseRightsizeOptions(config.RecommendConfig{MinCPU: "lots"}); err == nil {
+		t.Fatalf("expected error for invalid min_cpu")
+	}
+}
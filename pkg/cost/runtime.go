@@ -0,0 +1,80 @@
+package cost
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Period is the billing window pod runtime is prorated against.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// MonthPeriod returns the calendar month containing t.
+func MonthPeriod(t time.Time) Period {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return Period{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// runtimeFraction returns the share of period the pod runs for. Finished pods
+// stop at their last container termination. Running and pending pods stop at
+// now, or with project at the end of the period, as if they keep running.
+func runtimeFraction(pod corev1.Pod, period Period, now time.Time, project bool) float64 {
+	total := period.End.Sub(period.Start)
+	if total <= 0 {
+		return 0
+	}
+
+	start := pod.CreationTimestamp.Time
+	if pod.Status.StartTime != nil {
+		start = pod.Status.StartTime.Time
+	}
+	if start.IsZero() {
+		start = now
+	}
+
+	end := now
+	if project {
+		end = period.End
+	}
+	if isTerminated(pod.Status.Phase) {
+		end = start
+		if finished := lastTermination(pod); finished.After(start) {
+			end = finished
+		}
+	}
+
+	if start.Before(period.Start) {
+		start = period.Start
+	}
+	if end.After(period.End) {
+		end = period.End
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return float64(end.Sub(start)) / float64(total)
+}
+
+// lastTermination returns the latest container termination time of pod.
+func lastTermination(pod corev1.Pod) time.Time {
+	var latest time.Time
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if cs.State.Terminated != nil && cs.State.Terminated.FinishedAt.After(latest) {
+			latest = cs.State.Terminated.FinishedAt.Time
+		}
+	}
+	return latest
+}
+
+// scaleQuantity returns q multiplied by f, rounded down to a millivalue.
+func scaleQuantity(q resource.Quantity, f float64) resource.Quantity {
+	if f >= 1 {
+		return q
+	}
+	return *resource.NewMilliQuantity(int64(float64(q.MilliValue())*f), q.Format)
+}
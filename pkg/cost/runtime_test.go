@@ -0,0 +1,137 @@
+package cost
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	"github.com/newman-bot/kfin/pkg/pricing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func finishedPod(name string, phase corev1.PodPhase, start, finish time.Time) corev1.Pod {
+	pod := testPod("batch", name, testContainer("worker", "1", "1Gi"))
+	pod.Status.Phase = phase
+	pod.Status.StartTime = &metav1.Time{Time: start}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name: "worker",
+		State: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.Time{Time: finish}},
+		},
+	}}
+	return pod
+}
+
+func TestCalculate_ExcludesTerminatedPods(t *testing.T) {
+	now := time.Date(2024, time.April, 16, 0, 0, 0, 0, time.UTC)
+	running := testPod("web", "api", testContainer("app", "1", "1Gi"))
+	running.Status.Phase = corev1.PodRunning
+	pods := []corev1.Pod{
+		running,
+		finishedPod("job-1", corev1.PodSucceeded, now.Add(-2*time.Hour), now.Add(-time.Hour)),
+		finishedPod("evicted", corev1.PodFailed, now.Add(-2*time.Hour), now.Add(-time.Hour)),
+	}
+
+	report := newTestCalculator(t, nil).Calculate(Inventory{Pods: pods, CollectedAt: now})
+	if len(report.Pods) != 1 || report.Pods[0].Name != "api" {
+		t.Fatalf("expected only the running pod, got %+v", report.Pods)
+	}
+	if report.TerminatedExcluded != 2 {
+		t.Fatalf("expected 2 terminated pods excluded, got %d", report.TerminatedExcluded)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Pricing.IncludeTerminated = true
+	report = newTestCalculator(t, cfg).Calculate(Inventory{Pods: pods, CollectedAt: now})
+	if len(report.Pods) != 3 || report.TerminatedExcluded != 0 {
+		t.Fatalf("expected all pods with include_terminated, got %d (%d excluded)", len(report.Pods), report.TerminatedExcluded)
+	}
+}
+
+func TestCalculate_ProratesByRuntime(t *testing.T) {
+	now := time.Date(2024, time.April, 16, 0, 0, 0, 0, time.UTC)
+	period := MonthPeriod(now)
+	cfg := config.DefaultConfig()
+	cfg.Pricing.IncludeTerminated = true
+	cfg.Pricing.ProrateRuntime = true
+	calc := newTestCalculator(t, cfg)
+
+	// Started before the month and still running: the month to date.
+	longRunning := testPod("web", "api", testContainer("app", "1", "1Gi"))
+	longRunning.Status.Phase = corev1.PodRunning
+	longRunning.Status.StartTime = &metav1.Time{Time: period.Start.AddDate(0, 0, -3)}
+	// Started mid-month and still running: up to now.
+	midMonth := testPod("web", "canary", testContainer("app", "1", "1Gi"))
+	midMonth.Status.Phase = corev1.PodRunning
+	midMonth.Status.StartTime = &metav1.Time{Time: now.AddDate(0, 0, -5)}
+	// Ran for three days.
+	job := finishedPod("job-1", corev1.PodSucceeded, period.Start.AddDate(0, 0, 2), period.Start.AddDate(0, 0, 5))
+
+	report := calc.Calculate(Inventory{Pods: []corev1.Pod{longRunning, midMonth, job}, CollectedAt: now})
+
+	full := HoursPerMonth*0.02 + HoursPerMonth*0.005
+	want := map[string]float64{"api": 15.0 / 30, "canary": 5.0 / 30, "job-1": 3.0 / 30}
+	if !report.Prorated || report.Projected || !report.Period.Start.Equal(period.Start) {
+		t.Fatalf("expected prorated report over %v, got %v", period.Start, report.Period.Start)
+	}
+	for _, p := range report.Pods {
+		if !approxEqual(p.RuntimeFraction, want[p.Name]) {
+			t.Fatalf("expected %s runtime fraction %.4f, got %.4f", p.Name, want[p.Name], p.RuntimeFraction)
+		}
+		if !approxEqual(p.Cost, full*want[p.Name]) {
+			t.Fatalf("expected %s cost %.4f, got %.4f", p.Name, full*want[p.Name], p.Cost)
+		}
+	}
+}
+
+func TestRuntimeFraction_TerminatedWithoutTimestamps(t *testing.T) {
+	now := time.Date(2024, time.April, 16, 0, 0, 0, 0, time.UTC)
+	pod := testPod("batch", "job-1", testContainer("worker", "1", "1Gi"))
+	pod.Status.Phase = corev1.PodFailed
+	pod.Status.StartTime = &metav1.Time{Time: now.Add(-time.Hour)}
+
+	if f := runtimeFraction(pod, MonthPeriod(now), now, false); f != 0 {
+		t.Fatalf("expected no runtime without a termination time, got %f", f)
+	}
+}
+
+func TestRuntimeFraction_RunningStopsAtNow(t *testing.T) {
+	period := MonthPeriod(time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC))
+	pod := testPod("web", "canary", testContainer("app", "1", "1Gi"))
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.StartTime = &metav1.Time{Time: period.Start.AddDate(0, 0, 10)}
+	// Observed an hour after it started, the pod has run for an hour.
+	now := pod.Status.StartTime.Add(time.Hour)
+
+	if f := runtimeFraction(pod, period, now, false); !approxEqual(f, 1.0/(30*24)) {
+		t.Fatalf("expected running pod billed up to now (%.6f), got %.6f", 1.0/(30*24), f)
+	}
+	// Collected after the period ended, the pod stops at period end.
+	if f := runtimeFraction(pod, period, period.End.Add(48*time.Hour), false); !approxEqual(f, 20.0/30) {
+		t.Fatalf("expected running pod clamped to period end (%.4f), got %.4f", 20.0/30, f)
+	}
+}
+
+func TestRuntimeFraction_RunningProjectsToPeriodEnd(t *testing.T) {
+	period := MonthPeriod(time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC))
+	pod := testPod("web", "canary", testContainer("app", "1", "1Gi"))
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.StartTime = &metav1.Time{Time: period.Start.AddDate(0, 0, 10)}
+	// Projected, a pod observed a day after it started is billed for the
+	// 20 days left in the month, not the one day it has run so far.
+	now := pod.Status.StartTime.Add(24 * time.Hour)
+
+	if f := runtimeFraction(pod, period, now, true); !approxEqual(f, 20.0/30) {
+		t.Fatalf("expected running pod projected to month end (%.4f), got %.4f", 20.0/30, f)
+	}
+}
+
+func TestNewCalculator_ProjectRequiresProrate(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.ProjectRuntime = true
+	if _, err := NewCalculator(context.Background(), cfg, pricing.NewStaticProvider(0.02, 0.005)); err == nil {
+		t.Fatalf("expected error for project_runtime without prorate_runtime")
+	}
+}
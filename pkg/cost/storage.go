@@ -0,0 +1,147 @@
+package cost
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// VolumeCost is the monthly cost of one persistent volume or claim. Claim is
+// empty for volumes with no live claim. Waste marks capacity nobody can use:
+// volumes that are Available, Released or Failed.
+type VolumeCost struct {
+	Name         string
+	Claim        string
+	Namespace    string
+	StorageClass string
+	Phase        string
+	Capacity     resource.Quantity
+	Cost         float64
+	Waste        bool
+	// Pods in the report that mount the claim.
+	Pods []string
+}
+
+// StorageRate returns the $/GB-month rate for a storage class, falling back
+// to pricing.storage.default_per_gb_month.
+func (c *Calculator) StorageRate(class string) float64 {
+	if rate, ok := c.cfg.Pricing.Storage.ByClass[class]; ok {
+		return rate
+	}
+	return c.cfg.Pricing.Storage.DefaultPerGBMonth
+}
+
+// volumeCost prices capacity of the given storage class per GB-month.
+func (c *Calculator) volumeCost(class string, capacity resource.Quantity) float64 {
+	return float64(capacity.Value()) / BytesPerGB * c.StorageRate(class)
+}
+
+// priceVolumes prices every bound claim and every volume without one. Bound
+// claims are split evenly across the report's pods that mount them; claims no
+// pod mounts are charged to their namespace. Pending claims have no volume
+// yet and cost nothing.
+func (c *Calculator) priceVolumes(report *Report, claims []corev1.PersistentVolumeClaim, volumes []corev1.PersistentVolume) {
+	pvByName := make(map[string]corev1.PersistentVolume, len(volumes))
+	for _, pv := range volumes {
+		pvByName[pv.Name] = pv
+	}
+
+	mounts := make(map[string][]int)
+	for i, pod := range report.Pods {
+		for _, claim := range pod.Claims {
+			key := pod.Namespace + "/" + claim
+			mounts[key] = append(mounts[key], i)
+		}
+	}
+
+	claimed := make(map[string]bool)
+	for _, pvc := range claims {
+		if pvc.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+		key := pvc.Namespace + "/" + pvc.Name
+		vc := VolumeCost{
+			Name:         pvc.Spec.VolumeName,
+			Claim:        pvc.Name,
+			Namespace:    pvc.Namespace,
+			StorageClass: claimStorageClass(pvc),
+			Phase:        string(corev1.VolumeBound),
+			Capacity:     pvc.Status.Capacity.Storage().DeepCopy(),
+		}
+		if pv, ok := pvByName[pvc.Spec.VolumeName]; ok {
+			claimed[pv.Name] = true
+			if pv.Spec.StorageClassName != "" {
+				vc.StorageClass = pv.Spec.StorageClassName
+			}
+			if q, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+				vc.Capacity = q.DeepCopy()
+			}
+		}
+		vc.Cost = c.volumeCost(vc.StorageClass, vc.Capacity)
+
+		pods := mounts[key]
+		for _, idx := range pods {
+			share := vc.Cost / float64(len(pods))
+			report.Pods[idx].StorageCost += share
+			report.Pods[idx].Cost += share
+			vc.Pods = append(vc.Pods, report.Pods[idx].Name)
+		}
+		report.StorageCost += vc.Cost
+		report.Volumes = append(report.Volumes, vc)
+	}
+
+	for _, pv := range volumes {
+		if claimed[pv.Name] || pv.Status.Phase == corev1.VolumeBound || pv.Status.Phase == corev1.VolumePending {
+			continue
+		}
+		vc := VolumeCost{
+			Name:         pv.Name,
+			StorageClass: pv.Spec.StorageClassName,
+			Phase:        string(pv.Status.Phase),
+			Capacity:     pv.Spec.Capacity.Storage().DeepCopy(),
+			Waste:        true,
+		}
+		if pv.Spec.ClaimRef != nil {
+			vc.Namespace = pv.Spec.ClaimRef.Namespace
+		}
+		vc.Cost = c.volumeCost(vc.StorageClass, vc.Capacity)
+		report.StorageCost += vc.Cost
+		report.StorageWasteCost += vc.Cost
+		report.Volumes = append(report.Volumes, vc)
+	}
+
+	sort.SliceStable(report.Volumes, func(i, j int) bool {
+		return report.Volumes[i].Cost > report.Volumes[j].Cost
+	})
+}
+
+func claimStorageClass(pvc corev1.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName != nil {
+		return *pvc.Spec.StorageClassName
+	}
+	return ""
+}
+
+// podClaims returns the names of the PersistentVolumeClaims pod mounts.
+func podClaims(pod corev1.Pod) []string {
+	var claims []string
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim != nil {
+			claims = append(claims, v.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return claims
+}
+
+// unmountedClaimCosts returns the cost of bound claims no pod in the report
+// mounts, by namespace.
+func unmountedClaimCosts(volumes []VolumeCost) map[string]float64 {
+	out := make(map[string]float64)
+	for _, v := range volumes {
+		if v.Claim != "" && len(v.Pods) == 0 {
+			out[v.Namespace] += v.Cost
+		}
+	}
+	return out
+}
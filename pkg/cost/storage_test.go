@@ -0,0 +1,99 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/newman-bot/kfin/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testClaim(namespace, name, volume, class, size string) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: volume, StorageClassName: &class},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase:    corev1.ClaimBound,
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+		},
+	}
+}
+
+func testVolume(name, class, size string, phase corev1.PersistentVolumePhase) corev1.PersistentVolume {
+	return corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PersistentVolumeSpec{
+			StorageClassName: class,
+			Capacity:         corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+		},
+		Status: corev1.PersistentVolumeStatus{Phase: phase},
+	}
+}
+
+func mountClaim(pod corev1.Pod, claim string) corev1.Pod {
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: claim,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+		},
+	})
+	return pod
+}
+
+func TestCalculate_StorageCosts(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Pricing.Storage.DefaultPerGBMonth = 0.10
+	cfg.Pricing.Storage.ByClass = map[string]float64{"gp3": 0.08, "local-path": 0}
+	calc := newTestCalculator(t, cfg)
+
+	db := mountClaim(testPod("data", "db-0", testContainer("db", "", "")), "data-db-0")
+	shared1 := mountClaim(testPod("web", "api-1", testContainer("app", "", "")), "shared")
+	shared2 := mountClaim(testPod("web", "api-2", testContainer("app", "", "")), "shared")
+
+	report := calc.Calculate(Inventory{
+		Pods: []corev1.Pod{db, shared1, shared2},
+		PersistentVolumeClaims: []corev1.PersistentVolumeClaim{
+			testClaim("data", "data-db-0", "pv-db", "gp3", "100Gi"),
+			testClaim("web", "shared", "pv-shared", "longhorn", "10Gi"),
+			testClaim("web", "scratch", "pv-scratch", "local-path", "50Gi"),
+			testClaim("logs", "archive", "pv-archive", "gp3", "50Gi"),
+		},
+		PersistentVolumes: []corev1.PersistentVolume{
+			testVolume("pv-db", "gp3", "100Gi", corev1.VolumeBound),
+			testVolume("pv-old", "gp3", "20Gi", corev1.VolumeReleased),
+			testVolume("pv-spare", "", "5Gi", corev1.VolumeAvailable),
+		},
+	})
+
+	costs := map[string]float64{}
+	for _, p := range report.Pods {
+		costs[p.Name] = p.StorageCost
+	}
+	if !approxEqual(costs["db-0"], 8) || !approxEqual(costs["api-1"], 0.5) || !approxEqual(costs["api-2"], 0.5) {
+		t.Fatalf("unexpected pod storage costs %+v", costs)
+	}
+
+	wantWaste := 20*0.08 + 5*0.10
+	if !approxEqual(report.StorageWasteCost, wantWaste) {
+		t.Fatalf("expected waste %.2f, got %.2f", wantWaste, report.StorageWasteCost)
+	}
+	wantStorage := 8 + 1 + 0 + 50*0.08 + wantWaste
+	if !approxEqual(report.StorageCost, wantStorage) {
+		t.Fatalf("expected storage %.2f, got %.2f", wantStorage, report.StorageCost)
+	}
+	if !approxEqual(report.TotalCost, report.HardwareCost+report.ElecCost+report.ControlPlaneCost+wantStorage) {
+		t.Fatalf("expected storage in total cost, got %.2f", report.TotalCost)
+	}
+
+	// The unmounted claim is charged to its namespace.
+	var logs NamespaceCost
+	for _, ns := range report.Namespaces {
+		if ns.Name == "logs" {
+			logs = ns
+		}
+	}
+	if !approxEqual(logs.Cost, 4) || !approxEqual(logs.StorageCost, 4) || logs.Pods != 0 {
+		t.Fatalf("expected unmounted claim on logs namespace, got %+v", logs)
+	}
+}
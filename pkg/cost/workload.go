@@ -0,0 +1,119 @@
+package cost
+
+import (
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkloadKindPod is used for pods without a controlling owner.
+const WorkloadKindPod = "Pod"
+
+// WorkloadRef identifies the top-level controller that owns a pod.
+type WorkloadRef struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+func (w WorkloadRef) String() string {
+	return strings.ToLower(w.Kind) + "/" + w.Name
+}
+
+// WorkloadCost is the summed cost of all pods owned by one workload.
+type WorkloadCost struct {
+	WorkloadRef
+	Pods int
+	Cost float64
+}
+
+type ownerKey struct {
+	namespace string
+	name      string
+}
+
+// ownerIndex maps intermediate controllers (ReplicaSets and Jobs) to their
+// own controller so pods can be attributed to Deployments and CronJobs.
+type ownerIndex struct {
+	replicaSets map[ownerKey]*metav1.OwnerReference
+	jobs        map[ownerKey]*metav1.OwnerReference
+}
+
+func newOwnerIndex(replicaSets []appsv1.ReplicaSet, jobs []batchv1.Job) ownerIndex {
+	idx := ownerIndex{
+		replicaSets: make(map[ownerKey]*metav1.OwnerReference, len(replicaSets)),
+		jobs:        make(map[ownerKey]*metav1.OwnerReference, len(jobs)),
+	}
+	for i := range replicaSets {
+		rs := &replicaSets[i]
+		idx.replicaSets[ownerKey{rs.Namespace, rs.Name}] = metav1.GetControllerOf(rs)
+	}
+	for i := range jobs {
+		job := &jobs[i]
+		idx.jobs[ownerKey{job.Namespace, job.Name}] = metav1.GetControllerOf(job)
+	}
+	return idx
+}
+
+// resolve walks the pod's controller reference up to its top-level workload.
+// When a ReplicaSet was not listed, the Deployment name is derived from the
+// pod-template-hash suffix.
+func (idx ownerIndex) resolve(pod corev1.Pod) WorkloadRef {
+	ref := metav1.GetControllerOf(&pod)
+	if ref == nil {
+		return WorkloadRef{Kind: WorkloadKindPod, Name: pod.Name, Namespace: pod.Namespace}
+	}
+
+	switch ref.Kind {
+	case "ReplicaSet":
+		if parent, ok := idx.replicaSets[ownerKey{pod.Namespace, ref.Name}]; ok {
+			if parent != nil {
+				return WorkloadRef{Kind: parent.Kind, Name: parent.Name, Namespace: pod.Namespace}
+			}
+			return WorkloadRef{Kind: ref.Kind, Name: ref.Name, Namespace: pod.Namespace}
+		}
+		if hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; hash != "" && strings.HasSuffix(ref.Name, "-"+hash) {
+			return WorkloadRef{Kind: "Deployment", Name: strings.TrimSuffix(ref.Name, "-"+hash), Namespace: pod.Namespace}
+		}
+	case "Job":
+		if parent := idx.jobs[ownerKey{pod.Namespace, ref.Name}]; parent != nil {
+			return WorkloadRef{Kind: parent.Kind, Name: parent.Name, Namespace: pod.Namespace}
+		}
+	}
+	return WorkloadRef{Kind: ref.Kind, Name: ref.Name, Namespace: pod.Namespace}
+}
+
+// summarizeWorkloads rolls pod costs up to their workloads, highest cost first.
+func summarizeWorkloads(pods []PodCost) []WorkloadCost {
+	byRef := make(map[WorkloadRef]*WorkloadCost)
+	var order []WorkloadRef
+	for _, p := range pods {
+		item, ok := byRef[p.Workload]
+		if !ok {
+			item = &WorkloadCost{WorkloadRef: p.Workload}
+			byRef[p.Workload] = item
+			order = append(order, p.Workload)
+		}
+		item.Pods++
+		item.Cost += p.Cost
+	}
+
+	out := make([]WorkloadCost, 0, len(order))
+	for _, ref := range order {
+		out = append(out, *byRef[ref])
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Cost == out[j].Cost {
+			if out[i].Namespace == out[j].Namespace {
+				return out[i].Name < out[j].Name
+			}
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Cost > out[j].Cost
+	})
+	return out
+}
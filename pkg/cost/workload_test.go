@@ -0,0 +1,69 @@
+package cost
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func controllerRef(kind, name string) []metav1.OwnerReference {
+	isController := true
+	return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &isController}}
+}
+
+func ownedPod(namespace, name, ownerKind, ownerName string, labels map[string]string) corev1.Pod {
+	pod := testPod(namespace, name, testContainer("app", "100m", "128Mi"))
+	pod.Labels = labels
+	if ownerKind != "" {
+		pod.OwnerReferences = controllerRef(ownerKind, ownerName)
+	}
+	return pod
+}
+
+func TestCalculate_WorkloadRollup(t *testing.T) {
+	calc := newTestCalculator(t, nil)
+	inv := Inventory{
+		Pods: []corev1.Pod{
+			ownedPod("shop", "checkout-7d9f-a", "ReplicaSet", "checkout-7d9f", nil),
+			ownedPod("shop", "checkout-7d9f-b", "ReplicaSet", "checkout-7d9f", nil),
+			ownedPod("shop", "cart-5c6b-a", "ReplicaSet", "cart-5c6b", map[string]string{"pod-template-hash": "5c6b"}),
+			ownedPod("batch", "report-123-x", "Job", "report-123", nil),
+			ownedPod("db", "pg-0", "StatefulSet", "pg", nil),
+			ownedPod("default", "debug", "", "", nil),
+		},
+		ReplicaSets: []appsv1.ReplicaSet{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "checkout-7d9f", OwnerReferences: controllerRef("Deployment", "checkout")},
+		}},
+		Jobs: []batchv1.Job{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "batch", Name: "report-123", OwnerReferences: controllerRef("CronJob", "report")},
+		}},
+	}
+
+	report := calc.Calculate(inv)
+
+	want := map[WorkloadRef]int{
+		{Kind: "Deployment", Name: "checkout", Namespace: "shop"}:    2,
+		{Kind: "Deployment", Name: "cart", Namespace: "shop"}:        1,
+		{Kind: "CronJob", Name: "report", Namespace: "batch"}:        1,
+		{Kind: "StatefulSet", Name: "pg", Namespace: "db"}:           1,
+		{Kind: WorkloadKindPod, Name: "debug", Namespace: "default"}: 1,
+	}
+	if len(report.Workloads) != len(want) {
+		t.Fatalf("expected %d workloads, got %d: %+v", len(want), len(report.Workloads), report.Workloads)
+	}
+	for _, w := range report.Workloads {
+		pods, ok := want[w.WorkloadRef]
+		if !ok {
+			t.Fatalf("unexpected workload %+v", w.WorkloadRef)
+		}
+		if w.Pods != pods {
+			t.Fatalf("expected %d pods for %s, got %d", pods, w.String(), w.Pods)
+		}
+	}
+	if report.Workloads[0].Name != "checkout" {
+		t.Fatalf("expected checkout to be the most expensive workload, got %s", report.Workloads[0].Name)
+	}
+}
@@ -2,22 +2,23 @@ package pdf
 
 import (
 	"fmt"
+	"math"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/jung-kurt/gofpdf"
+	"github.com/newman-bot/kfin/pkg/cost"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type ReportData struct {
-	PodCosts         []PodCost
-	TotalCost        float64
-	HardwareCost     float64
-	ElecCost         float64
-	ControlPlaneCost float64
-	Nodes            []NodeInfo
-	GeneratedAt      time.Time
-	ContextName      string
-	ClusterName      string
+	Report      *cost.Report
+	GeneratedAt time.Time
+	ContextName string
+	ClusterName string
+	// GroupBy adds a cost table grouped by these dimensions when set.
+	GroupBy []cost.GroupDimension
 }
 
 type PodCost struct {
@@ -25,24 +26,87 @@ type PodCost struct {
 	Namespace string
 	CPU       string
 	Memory    string
+	Extended  corev1.ResourceList
 	Cost      float64
 }
 
-type NodeInfo struct {
-	Name         string
-	MemoryGB     float64
-	HardwareCost float64
-	ElecCost     float64
-	TotalCost    float64
+// FleetData is the input of a fleet PDF: a summary of every cluster followed
+// by each cluster's report, in the order of Fleet.Clusters.
+type FleetData struct {
+	Fleet       *cost.Fleet
+	GeneratedAt time.Time
+	Clusters    []ReportData
 }
 
-type namespaceSummary struct {
-	Name string
-	Pods int
-	Cost float64
+func Generate(data ReportData, filename string) error {
+	pdf := newDocument()
+	writeReport(pdf, data)
+	return pdf.OutputFileAndClose(filename)
 }
 
-func Generate(data ReportData, filename string) error {
+// GenerateFleet writes a fleet summary page, then one report per cluster.
+func GenerateFleet(data FleetData, filename string) error {
+	pdf := newDocument()
+	fleet := data.Fleet
+
+	pdf.AddPage()
+	drawBanner(pdf, "kFIN Fleet Report", "Kubernetes fleet monthly cost breakdown",
+		fmt.Sprintf("Clusters: %d  |  Nodes: %d  |  Pods: %d  |  Total: %s", len(fleet.Clusters), fleet.Nodes, fleet.Pods, money(fleet.TotalCost)),
+		data.GeneratedAt)
+
+	clusterRows := make([][]string, 0, len(fleet.Clusters)+1)
+	for _, c := range fleet.Clusters {
+		r := c.Report
+		clusterRows = append(clusterRows, []string{
+			truncateWithDots(c.Context, 30),
+			r.Distribution.String(),
+			fmt.Sprintf("%d", len(r.Nodes)),
+			fmt.Sprintf("%d", len(r.Pods)),
+			money(r.IdleCost),
+			money(r.TotalCost),
+		})
+	}
+	clusterRows = append(clusterRows, []string{"TOTAL", "", fmt.Sprintf("%d", fleet.Nodes), fmt.Sprintf("%d", fleet.Pods), money(fleet.IdleCost), money(fleet.TotalCost)})
+	drawTable(
+		pdf,
+		"Clusters (Monthly)",
+		[]string{"CONTEXT", "DIST", "NODES", "PODS", "IDLE", "TOTAL"},
+		[]float64{62, 24, 20, 20, 28, 32},
+		[]string{"L", "L", "R", "R", "R", "R"},
+		clusterRows,
+	)
+
+	nsRows := make([][]string, 0, len(fleet.Namespaces))
+	for _, ns := range fleet.Namespaces {
+		if ns.Cost <= 0 {
+			continue
+		}
+		nsRows = append(nsRows, []string{
+			truncateWithDots(ns.Context, 30),
+			truncateWithDots(ns.Name, 36),
+			fmt.Sprintf("%d", ns.Pods),
+			money(ns.Cost),
+		})
+	}
+	if len(nsRows) == 0 {
+		nsRows = append(nsRows, []string{"No non-zero namespace costs", "-", "-", "-"})
+	}
+	drawTable(
+		pdf,
+		"Namespace Costs by Cluster (Monthly)",
+		[]string{"CONTEXT", "NAMESPACE", "PODS", "MONTHLY COST"},
+		[]float64{62, 72, 18, 34},
+		[]string{"L", "L", "R", "R"},
+		nsRows,
+	)
+
+	for _, cluster := range data.Clusters {
+		writeReport(pdf, cluster)
+	}
+	return pdf.OutputFileAndClose(filename)
+}
+
+func newDocument() *gofpdf.Fpdf {
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.SetMargins(12, 12, 12)
 	pdf.SetAutoPageBreak(true, 12)
@@ -55,17 +119,28 @@ func Generate(data ReportData, filename string) error {
 		pdf.SetTextColor(110, 110, 110)
 		pdf.CellFormat(0, 5, fmt.Sprintf("Generated by kfin  |  Page %d", pdf.PageNo()), "", 0, "C", false, 0, "")
 	})
+	return pdf
+}
 
-	nonZeroPods := filterAndSortPods(data.PodCosts)
-	nsSummary := summarizeNamespaces(nonZeroPods)
+// writeReport adds the pages of one cluster's report, starting on a new page.
+func writeReport(pdf *gofpdf.Fpdf, data ReportData) {
+	report := data.Report
+	nonZeroPods := filterAndSortPods(buildPodCosts(report))
+	nsSummary := nonZeroNamespaces(report.Namespaces)
 
 	pdf.AddPage()
 	drawReportHeader(pdf, data)
-	drawSummaryCards(pdf, data, len(nonZeroPods), len(nsSummary))
+	nsCount := 0
+	for _, ns := range nsSummary {
+		if ns.Name != cost.IdleNamespace {
+			nsCount++
+		}
+	}
+	drawSummaryCards(pdf, data, len(nonZeroPods), nsCount)
 
-	rows := make([][]string, 0, len(data.Nodes)+1)
+	rows := make([][]string, 0, len(report.Nodes)+1)
 	var nodeTotal float64
-	for _, n := range data.Nodes {
+	for _, n := range report.Nodes {
 		rows = append(rows, []string{
 			truncateWithDots(n.Name, 26),
 			fmt.Sprintf("%.1f GB", n.MemoryGB),
@@ -85,56 +160,323 @@ func Generate(data ReportData, filename string) error {
 		rows,
 	)
 
+	idleRows := make([][]string, 0, len(report.Nodes)+1)
+	for _, n := range report.Nodes {
+		idleRows = append(idleRows, []string{
+			truncateWithDots(n.Name, 26),
+			fmt.Sprintf("%s / %s", n.IdleCPU.String(), n.AllocatableCPU.String()),
+			fmt.Sprintf("%s / %s", n.IdleMemory.String(), n.AllocatableMemory.String()),
+			money(n.IdleCost),
+		})
+	}
+	idleRows = append(idleRows, []string{"TOTAL", "", "", money(report.IdleCost)})
+	drawTable(
+		pdf,
+		"Idle Capacity (Unrequested, Monthly)",
+		[]string{"NODE", "IDLE CPU", "IDLE MEMORY", "IDLE COST"},
+		[]float64{52, 40, 56, 38},
+		[]string{"L", "R", "R", "R"},
+		idleRows,
+	)
+
+	if len(report.Volumes) > 0 {
+		volumeRows := make([][]string, 0, len(report.Volumes)+1)
+		for _, v := range report.Volumes {
+			name := v.Claim
+			if v.Waste {
+				name = v.Name + " (" + strings.ToLower(v.Phase) + ")"
+			}
+			volumeRows = append(volumeRows, []string{
+				truncateWithDots(name, 32),
+				truncateWithDots(v.Namespace, 18),
+				truncateWithDots(v.StorageClass, 14),
+				v.Capacity.String(),
+				money(v.Cost),
+			})
+		}
+		volumeRows = append(volumeRows, []string{"TOTAL", "", "", "", money(report.StorageCost)})
+		drawTable(
+			pdf,
+			"Persistent Volumes (Monthly)",
+			[]string{"VOLUME", "NAMESPACE", "CLASS", "SIZE", "MONTHLY COST"},
+			[]float64{62, 38, 30, 22, 34},
+			[]string{"L", "L", "L", "R", "R"},
+			volumeRows,
+		)
+	}
+
+	if len(report.LoadBalancers) > 0 {
+		lbRows := make([][]string, 0, len(report.LoadBalancers)+1)
+		for _, lb := range report.LoadBalancers {
+			lbType := lb.Type
+			if lbType == "" {
+				lbType = "-"
+			}
+			lbRows = append(lbRows, []string{
+				truncateWithDots(lb.Name, 32),
+				truncateWithDots(lb.Namespace, 18),
+				lb.Kind,
+				lbType,
+				money(lb.Cost),
+			})
+		}
+		lbRows = append(lbRows, []string{"TOTAL", "", "", "", money(report.LoadBalancerCost)})
+		drawTable(
+			pdf,
+			"Load Balancers (Monthly)",
+			[]string{"NAME", "NAMESPACE", "KIND", "TYPE", "MONTHLY COST"},
+			[]float64{62, 38, 26, 26, 34},
+			[]string{"L", "L", "L", "L", "R"},
+			lbRows,
+		)
+	}
+
 	nsRows := make([][]string, 0, len(nsSummary))
 	for _, ns := range nsSummary {
+		pods := fmt.Sprintf("%d", ns.Pods)
+		if ns.Name == cost.IdleNamespace {
+			pods = "-"
+		}
+		owner, costCenter, budget, burn := orDash(ns.Owner), orDash(ns.CostCenter), "-", "-"
+		if ns.Name == cost.IdleNamespace {
+			owner = "-"
+		}
+		if b, ok := report.NamespaceBudget(ns.Name); ok {
+			budget, burn = money(b.Limit), percent(b.Burn)
+		}
 		nsRows = append(nsRows, []string{
-			truncateWithDots(ns.Name, 35),
-			fmt.Sprintf("%d", ns.Pods),
+			truncateWithDots(ns.Name, 24),
+			truncateWithDots(owner, 15),
+			truncateWithDots(costCenter, 14),
+			budget,
+			burn,
+			pods,
 			money(ns.Cost),
 		})
 	}
 	if len(nsRows) == 0 {
-		nsRows = append(nsRows, []string{"No non-zero namespace costs", "-", "-"})
+		nsRows = append(nsRows, append([]string{"No non-zero namespace costs"}, dashes(6)...))
 	}
 	drawTable(
 		pdf,
 		"Namespace Rollup (Non-Zero)",
-		[]string{"NAMESPACE", "PODS", "MONTHLY COST"},
-		[]float64{96, 24, 46},
-		[]string{"L", "R", "R"},
+		[]string{"NAMESPACE", "OWNER", "COST CENTER", "BUDGET", "BURN", "PODS", "MONTHLY COST"},
+		[]float64{48, 30, 28, 24, 16, 14, 26},
+		[]string{"L", "L", "L", "R", "R", "R", "R"},
 		nsRows,
 	)
 
+	var unownedRows [][]string
+	for _, ns := range cost.UnownedNamespaces(report) {
+		unownedRows = append(unownedRows, []string{
+			truncateWithDots(ns.Name, 50),
+			truncateWithDots(orDash(ns.CostCenter), 20),
+			fmt.Sprintf("%d", ns.Pods),
+			money(ns.Cost),
+		})
+	}
+	if len(unownedRows) > 0 {
+		drawTable(
+			pdf,
+			"Namespaces Without an Owner (kfin.io/owner)",
+			[]string{"NAMESPACE", "COST CENTER", "PODS", "MONTHLY COST"},
+			[]float64{96, 40, 20, 30},
+			[]string{"L", "L", "R", "R"},
+			unownedRows,
+		)
+	}
+
+	if len(report.Budgets) > 0 {
+		drawBudgetTable(pdf, report.Budgets)
+	}
+	if len(data.GroupBy) > 0 {
+		drawGroupTable(pdf, report, data.GroupBy)
+	}
+	if report.Chargeback != nil {
+		drawChargebackTable(pdf, report.Chargeback)
+	}
+
+	workloadRows := make([][]string, 0, len(report.Workloads))
+	for _, w := range report.Workloads {
+		if w.Cost <= 0 {
+			continue
+		}
+		workloadRows = append(workloadRows, []string{
+			truncateWithDots(w.Kind, 14),
+			truncateWithDots(w.Name, 34),
+			truncateWithDots(w.Namespace, 18),
+			fmt.Sprintf("%d", w.Pods),
+			money(w.Cost),
+		})
+	}
+	if len(workloadRows) == 0 {
+		workloadRows = append(workloadRows, []string{"-", "No non-zero workload costs", "-", "-", "-"})
+	}
+	drawTable(
+		pdf,
+		"Workload Rollup (Non-Zero)",
+		[]string{"KIND", "WORKLOAD", "NAMESPACE", "PODS", "MONTHLY COST"},
+		[]float64{30, 62, 38, 18, 38},
+		[]string{"L", "L", "L", "R", "R"},
+		workloadRows,
+	)
+
+	// Extended resource columns take their width from the pod and namespace
+	// columns so the table stays within the page.
+	extended := data.Report.ExtendedResources
+	extWidth := 0.0
+	if len(extended) > 0 {
+		extWidth = math.Min(24, 48/float64(len(extended)))
+	}
+	podHeaders := []string{"POD", "NAMESPACE", "CPU", "MEMORY"}
+	podWidths := []float64{58 - float64(len(extended))*extWidth*0.6, 38 - float64(len(extended))*extWidth*0.4, 24, 28}
+	podAligns := []string{"L", "L", "R", "R"}
+	for _, name := range extended {
+		podHeaders = append(podHeaders, strings.ToUpper(string(name)))
+		podWidths = append(podWidths, extWidth)
+		podAligns = append(podAligns, "R")
+	}
+	podHeaders = append(podHeaders, "MONTHLY COST")
+	podWidths = append(podWidths, 18+24)
+	podAligns = append(podAligns, "R")
+
 	podRows := make([][]string, 0, len(nonZeroPods)+1)
 	var podTotal float64
 	for _, p := range nonZeroPods {
-		podRows = append(podRows, []string{
-			truncateWithDots(p.Name, 30),
-			truncateWithDots(p.Namespace, 18),
+		row := []string{
+			truncateWithDots(p.Name, int(30*podWidths[0]/58)),
+			truncateWithDots(p.Namespace, int(18*podWidths[1]/38)),
 			p.CPU,
 			p.Memory,
-			money(p.Cost),
-		})
+		}
+		for _, name := range extended {
+			cell := "-"
+			if q, ok := p.Extended[name]; ok && !q.IsZero() {
+				cell = q.String()
+			}
+			row = append(row, cell)
+		}
+		podRows = append(podRows, append(row, money(p.Cost)))
 		podTotal += p.Cost
 	}
 	if len(podRows) == 0 {
-		podRows = append(podRows, []string{"No non-zero pod costs", "-", "-", "-", "-"})
+		podRows = append(podRows, append([]string{"No non-zero pod costs"}, dashes(len(podHeaders)-1)...))
 	} else {
-		podRows = append(podRows, []string{"TOTAL", "", "", "", money(podTotal)})
+		podRows = append(podRows, append(append([]string{"TOTAL"}, make([]string, len(podHeaders)-2)...), money(podTotal)))
 	}
 	drawTable(
 		pdf,
 		"Pod Costs (Non-Zero, Highest First)",
-		[]string{"POD", "NAMESPACE", "CPU REQ", "MEM REQ", "MONTHLY COST"},
-		[]float64{58, 38, 24, 28, 18 + 24},
-		[]string{"L", "L", "R", "R", "R"},
+		podHeaders,
+		podWidths,
+		podAligns,
 		podRows,
 	)
+}
 
-	return pdf.OutputFileAndClose(filename)
+// drawGroupTable draws non-zero group costs with one column per dimension,
+// sharing 120mm between them.
+func drawGroupTable(pdf *gofpdf.Fpdf, report *cost.Report, dims []cost.GroupDimension) {
+	names := make([]string, len(dims))
+	headers := make([]string, 0, len(dims)+2)
+	widths := make([]float64, 0, len(dims)+2)
+	aligns := make([]string, 0, len(dims)+2)
+	keyWidth := 120 / float64(len(dims))
+	keyChars := int(keyWidth / 2)
+	for i, d := range dims {
+		names[i] = d.String()
+		headers = append(headers, strings.ToUpper(truncateWithDots(names[i], keyChars)))
+		widths = append(widths, keyWidth)
+		aligns = append(aligns, "L")
+	}
+	headers = append(headers, "PODS", "MONTHLY COST")
+	widths = append(widths, 24, 42)
+	aligns = append(aligns, "R", "R")
+
+	var rows [][]string
+	var total float64
+	for _, g := range cost.GroupCosts(report, dims) {
+		total += g.Cost
+		if g.Cost <= 0 {
+			continue
+		}
+		row := make([]string, 0, len(headers))
+		for _, k := range g.Keys {
+			row = append(row, truncateWithDots(k, keyChars))
+		}
+		rows = append(rows, append(row, fmt.Sprintf("%d", g.Pods), money(g.Cost)))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, append([]string{"No non-zero group costs"}, dashes(len(headers)-1)...))
+	} else {
+		rows = append(rows, append(append([]string{"TOTAL"}, make([]string, len(headers)-2)...), money(total)))
+	}
+	drawTable(pdf, fmt.Sprintf("Costs by %s (Non-Zero)", strings.Join(names, ", ")), headers, widths, aligns, rows)
+}
+
+// drawChargebackTable lists the shared pool breakdown, then each tenant's
+// invoice line.
+func drawChargebackTable(pdf *gofpdf.Fpdf, cb *cost.Chargeback) {
+	shared := "none"
+	if len(cb.SharedNamespaces) > 0 {
+		shared = strings.Join(cb.SharedNamespaces, ", ")
+	}
+	rows := [][]string{
+		{truncateWithDots("Shared namespaces: "+shared, 50), "", money(cb.SharedNamespaceCost), ""},
+		{"Idle capacity", "", money(cb.IdleCost), ""},
+		{"Control plane", "", money(cb.ControlPlaneCost), ""},
+		{"Unallocated node cost", "", money(cb.UnallocatedCost), ""},
+	}
+	if cb.OverAllocatedCost > 0 {
+		rows = append(rows, []string{"Pod prices above node cost, scaled out", "-" + money(cb.OverAllocatedCost), "", ""})
+	}
+	for _, t := range cb.Tenants {
+		rows = append(rows, []string{truncateWithDots(t.Namespace, 50), money(t.Direct), money(t.Shared), money(t.Total)})
+	}
+	if len(cb.Tenants) == 0 {
+		rows = append(rows, []string{"No tenant namespaces to charge back", "-", "-", "-"})
+	}
+	rows = append(rows, []string{"TOTAL", "", money(cb.SharedCost), money(cb.TotalCost)})
+	drawTable(
+		pdf,
+		fmt.Sprintf("Chargeback (%s split of shared cost)", cb.Method),
+		[]string{"TENANT / SHARED COST", "DIRECT", "SHARED", "TOTAL"},
+		[]float64{96, 30, 30, 30},
+		[]string{"L", "R", "R", "R"},
+		rows,
+	)
+}
+
+// drawBudgetTable lists every evaluated budget, highest burn first.
+func drawBudgetTable(pdf *gofpdf.Fpdf, budgets []cost.BudgetResult) {
+	rows := make([][]string, 0, len(budgets))
+	for _, b := range budgets {
+		rows = append(rows, []string{
+			strings.ToUpper(b.Status.String()),
+			b.Scope,
+			truncateWithDots(b.Name, 36),
+			money(b.Limit),
+			money(b.Cost),
+			percent(b.Burn),
+		})
+	}
+	drawTable(
+		pdf,
+		"Budgets (Monthly)",
+		[]string{"STATUS", "SCOPE", "NAME", "BUDGET", "COST", "BURN"},
+		[]float64{22, 24, 68, 26, 26, 20},
+		[]string{"L", "L", "L", "R", "R", "R"},
+		rows,
+	)
 }
 
 func drawReportHeader(pdf *gofpdf.Fpdf, data ReportData) {
+	drawBanner(pdf, "kFIN Cost Report", "Kubernetes cluster monthly cost breakdown",
+		fmt.Sprintf("Context: %s  |  Cluster: %s  |  Distribution: %s  |  Basis: %s", data.ContextName, data.ClusterName, data.Report.Distribution, data.Report.CostBasis),
+		data.GeneratedAt)
+}
+
+func drawBanner(pdf *gofpdf.Fpdf, title, subtitle, detail string, generatedAt time.Time) {
 	pdf.SetFillColor(18, 28, 31)
 	pdf.SetDrawColor(18, 28, 31)
 	pdf.Rect(12, 12, 186, 24, "FD")
@@ -142,22 +484,22 @@ func drawReportHeader(pdf *gofpdf.Fpdf, data ReportData) {
 	pdf.SetTextColor(0, 194, 146)
 	pdf.SetFont("Arial", "B", 22)
 	pdf.SetXY(16, 16)
-	pdf.CellFormat(120, 8, "kFIN Cost Report", "", 0, "L", false, 0, "")
+	pdf.CellFormat(120, 8, title, "", 0, "L", false, 0, "")
 
 	pdf.SetTextColor(228, 232, 235)
 	pdf.SetFont("Arial", "", 10)
 	pdf.SetXY(16, 25)
-	pdf.CellFormat(120, 6, "Kubernetes cluster monthly cost breakdown", "", 0, "L", false, 0, "")
+	pdf.CellFormat(120, 6, subtitle, "", 0, "L", false, 0, "")
 	pdf.SetFont("Arial", "", 9)
 	pdf.SetXY(16, 30)
-	pdf.CellFormat(120, 5, fmt.Sprintf("Context: %s  |  Cluster: %s", data.ContextName, data.ClusterName), "", 0, "L", false, 0, "")
+	pdf.CellFormat(120, 5, detail, "", 0, "L", false, 0, "")
 
 	pdf.SetFont("Arial", "", 9)
 	pdf.SetXY(146, 16)
 	pdf.CellFormat(48, 6, "Generated", "", 0, "R", false, 0, "")
 	pdf.SetFont("Arial", "B", 10)
 	pdf.SetXY(146, 22)
-	pdf.CellFormat(48, 6, data.GeneratedAt.Format("2006-01-02 15:04 MST"), "", 0, "R", false, 0, "")
+	pdf.CellFormat(48, 6, generatedAt.Format("2006-01-02 15:04 MST"), "", 0, "R", false, 0, "")
 	pdf.Ln(30)
 }
 
@@ -173,20 +515,24 @@ func drawSummaryCards(pdf *gofpdf.Fpdf, data ReportData, nonZeroPods int, namesp
 	pdf.SetXY(x+4, y+4)
 	pdf.SetTextColor(52, 58, 64)
 	pdf.SetFont("Arial", "", 9)
-	pdf.CellFormat(36, 5, "TOTAL MONTHLY", "", 0, "L", false, 0, "")
-	pdf.CellFormat(34, 5, "HARDWARE", "", 0, "L", false, 0, "")
-	pdf.CellFormat(34, 5, "ELECTRICITY", "", 0, "L", false, 0, "")
-	pdf.CellFormat(34, 5, "CONTROL PLANE", "", 0, "L", false, 0, "")
-	pdf.CellFormat(34, 5, "PODS / NS", "", 0, "L", false, 0, "")
+	pdf.CellFormat(28, 5, "TOTAL MONTHLY", "", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 5, "HARDWARE", "", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 5, "ELECTRICITY", "", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 5, "CTRL PLANE", "", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 5, "STORAGE", "", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 5, "LOAD BAL.", "", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 5, "PODS / NS", "", 0, "L", false, 0, "")
 
 	pdf.SetXY(x+4, y+10)
 	pdf.SetTextColor(16, 24, 32)
-	pdf.SetFont("Arial", "B", 12)
-	pdf.CellFormat(36, 7, money(data.TotalCost), "", 0, "L", false, 0, "")
-	pdf.CellFormat(34, 7, money(data.HardwareCost), "", 0, "L", false, 0, "")
-	pdf.CellFormat(34, 7, money(data.ElecCost), "", 0, "L", false, 0, "")
-	pdf.CellFormat(34, 7, money(data.ControlPlaneCost), "", 0, "L", false, 0, "")
-	pdf.CellFormat(34, 7, fmt.Sprintf("%d / %d", nonZeroPods, namespaces), "", 0, "L", false, 0, "")
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(28, 7, money(data.Report.TotalCost), "", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 7, money(data.Report.HardwareCost), "", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 7, money(data.Report.ElecCost), "", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 7, money(data.Report.ControlPlaneCost), "", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 7, money(data.Report.StorageCost), "", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 7, money(data.Report.LoadBalancerCost), "", 0, "L", false, 0, "")
+	pdf.CellFormat(24, 7, fmt.Sprintf("%d / %d", nonZeroPods, namespaces), "", 0, "L", false, 0, "")
 
 	pdf.SetY(y + h + 4)
 }
@@ -252,6 +598,21 @@ func ensurePageSpace(pdf *gofpdf.Fpdf, needed float64) {
 	}
 }
 
+func buildPodCosts(report *cost.Report) []PodCost {
+	result := make([]PodCost, 0, len(report.Pods))
+	for _, pod := range report.Pods {
+		result = append(result, PodCost{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			CPU:       pod.CPU.String(),
+			Memory:    pod.Memory.String(),
+			Extended:  pod.Extended,
+			Cost:      pod.Cost,
+		})
+	}
+	return result
+}
+
 func filterAndSortPods(pods []PodCost) []PodCost {
 	filtered := make([]PodCost, 0, len(pods))
 	for _, p := range pods {
@@ -271,34 +632,39 @@ func filterAndSortPods(pods []PodCost) []PodCost {
 	return filtered
 }
 
-func summarizeNamespaces(pods []PodCost) []namespaceSummary {
-	byNS := make(map[string]*namespaceSummary)
-	for _, p := range pods {
-		item, ok := byNS[p.Namespace]
-		if !ok {
-			item = &namespaceSummary{Name: p.Namespace}
-			byNS[p.Namespace] = item
+func nonZeroNamespaces(namespaces []cost.NamespaceCost) []cost.NamespaceCost {
+	out := make([]cost.NamespaceCost, 0, len(namespaces))
+	for _, ns := range namespaces {
+		if ns.Cost > 0 {
+			out = append(out, ns)
 		}
-		item.Pods++
-		item.Cost += p.Cost
 	}
-	out := make([]namespaceSummary, 0, len(byNS))
-	for _, s := range byNS {
-		out = append(out, *s)
+	return out
+}
+
+func dashes(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = "-"
 	}
-	sort.Slice(out, func(i, j int) bool {
-		if out[i].Cost == out[j].Cost {
-			return out[i].Name < out[j].Name
-		}
-		return out[i].Cost > out[j].Cost
-	})
 	return out
 }
 
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func money(v float64) string {
 	return fmt.Sprintf("$%.2f", v)
 }
 
+func percent(ratio float64) string {
+	return fmt.Sprintf("%.0f%%", ratio*100)
+}
+
 func truncateWithDots(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
@@ -9,15 +9,17 @@ import (
 )
 
 // MCPProvider resolves pricing by executing an external command (typically an MCP client wrapper)
-// that prints JSON like: {"cpu_per_hour":0.031,"mem_per_gb_hour":0.004}
+// that prints JSON like: {"cpu_per_hour":0.031,"mem_per_gb_hour":0.004}. An optional
+// "extended_per_hour" object prices extended resources such as nvidia.com/gpu.
 type MCPProvider struct {
 	command string
 	args    []string
 }
 
 type mcpRatesOutput struct {
-	CPUPerHour   float64 `json:"cpu_per_hour"`
-	MemPerGBHour float64 `json:"mem_per_gb_hour"`
+	CPUPerHour      float64            `json:"cpu_per_hour"`
+	MemPerGBHour    float64            `json:"mem_per_gb_hour"`
+	ExtendedPerHour map[string]float64 `json:"extended_per_hour"`
 }
 
 func NewMCPProvider(command string, args []string) *MCPProvider {
@@ -51,6 +53,7 @@ func (p *MCPProvider) UsageRates(ctx context.Context) (UsageRates, error) {
 	return UsageRates{
 		CPUPerHour:   parsed.CPUPerHour,
 		MemPerGBHour: parsed.MemPerGBHour,
+		Extended:     parsed.ExtendedPerHour,
 	}, nil
 }
 
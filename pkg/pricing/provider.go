@@ -6,6 +6,10 @@ import "context"
 type UsageRates struct {
 	CPUPerHour   float64
 	MemPerGBHour float64
+	// Extended maps extended resource names (nvidia.com/gpu, hugepages-2Mi,
+	// ephemeral-storage, ...) to $/unit/hour. Byte-sized resources are priced
+	// per GB, everything else per unit requested.
+	Extended map[string]float64
 }
 
 // Provider supplies pricing rates from a backing source (config, MCP, etc.).
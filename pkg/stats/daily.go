@@ -0,0 +1,64 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DailyPoint is the average of a query's value over one UTC day.
+type DailyPoint struct {
+	Day     time.Time
+	Avg     float64
+	Samples int
+}
+
+// DailyAverages sums the response's series at each timestamp, as a sum()
+// query would, then averages those totals per UTC day. Days without samples
+// are left out. Points are ordered by day.
+func DailyAverages(resp *QueryRangeResponse) []DailyPoint {
+	if resp == nil {
+		return nil
+	}
+	totals := make(map[int64]float64)
+	for _, series := range resp.Data.Result {
+		for _, point := range series.Values {
+			if len(point) < 2 {
+				continue
+			}
+			ts, ok := point[0].(float64)
+			if !ok {
+				continue
+			}
+			raw, ok := point[1].(string)
+			if !ok {
+				continue
+			}
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil || math.IsNaN(v) {
+				continue
+			}
+			totals[int64(ts)] += v
+		}
+	}
+
+	byDay := make(map[time.Time]*DailyPoint)
+	for ts, v := range totals {
+		day := time.Unix(ts, 0).UTC().Truncate(24 * time.Hour)
+		p, ok := byDay[day]
+		if !ok {
+			p = &DailyPoint{Day: day}
+			byDay[day] = p
+		}
+		p.Avg += v
+		p.Samples++
+	}
+	out := make([]DailyPoint, 0, len(byDay))
+	for _, p := range byDay {
+		p.Avg /= float64(p.Samples)
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Day.Before(out[j].Day) })
+	return out
+}
@@ -0,0 +1,40 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDailyAverages(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) float64 { return float64(day.Add(d).Unix()) }
+	resp := &QueryRangeResponse{Status: "success"}
+	resp.Data.Result = []MatrixSeries{
+		{Values: [][]interface{}{
+			{at(1 * time.Hour), "1"},
+			{at(13 * time.Hour), "3"},
+			{at(25 * time.Hour), "10"},
+			{at(26 * time.Hour), "NaN"},
+		}},
+		{Values: [][]interface{}{
+			{at(1 * time.Hour), "2"},
+			{at(25 * time.Hour), "bogus"},
+		}},
+	}
+
+	got := DailyAverages(resp)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 days, got %+v", got)
+	}
+	// Day one: totals of 3 (1+2) and 3, day two: a single total of 10.
+	want := []DailyPoint{{Day: day, Avg: 3, Samples: 2}, {Day: day.AddDate(0, 0, 1), Avg: 10, Samples: 1}}
+	for i, w := range want {
+		if !got[i].Day.Equal(w.Day) || math.Abs(got[i].Avg-w.Avg) > 1e-9 || got[i].Samples != w.Samples {
+			t.Fatalf("day %d: expected %+v, got %+v", i, w, got[i])
+		}
+	}
+	if DailyAverages(nil) != nil {
+		t.Fatalf("expected no points for a nil response")
+	}
+}
@@ -0,0 +1,33 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Network series come from the pod sandbox, so they carry no container label.
+const networkTransmitQueryTemplate = `sum by (%s) (rate(container_network_transmit_bytes_total{pod!=""}[5m]))`
+
+// NetworkTransmitQuery returns the PromQL for transmitted bytes per second
+// grouped at level. LevelContainer is treated as LevelPod.
+func NetworkTransmitQuery(level UsageLevel) string {
+	if level == LevelContainer {
+		level = LevelPod
+	}
+	return fmt.Sprintf(networkTransmitQueryTemplate, level.labels())
+}
+
+// PodNetworkTransmit returns per-pod aggregates of transmitted bytes per
+// second over the window.
+func (c *Client) PodNetworkTransmit(ctx context.Context, start, end time.Time, step time.Duration) (map[PodKey]Aggregate, error) {
+	resp, err := c.QueryRange(ctx, NetworkTransmitQuery(LevelPod), start, end, step)
+	if err != nil {
+		return nil, fmt.Errorf("query network transmit: %w", err)
+	}
+	out := make(map[PodKey]Aggregate)
+	for key, agg := range AggregateSeries(resp) {
+		out[key.PodKey()] = agg
+	}
+	return out, nil
+}
@@ -0,0 +1,10 @@
+package stats
+
+import "testing"
+
+func TestNetworkTransmitQuery(t *testing.T) {
+	want := `sum by (namespace, pod) (rate(container_network_transmit_bytes_total{pod!=""}[5m]))`
+	if got := NetworkTransmitQuery(LevelContainer); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,45 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NodeLabel is the label node-level power and utilization series must be
+// grouped by. kube-prometheus-stack attaches it to node-exporter and Kepler
+// series through relabeling.
+const NodeLabel = "node"
+
+const (
+	// NodeCPUUtilizationQuery is the busy share of each node's CPUs (0..1).
+	NodeCPUUtilizationQuery = `1 - avg by (node) (rate(node_cpu_seconds_total{mode="idle"}[5m]))`
+	// KeplerNodePowerQuery is each node's measured platform power in watts.
+	KeplerNodePowerQuery = `sum by (node) (rate(kepler_node_platform_joules_total[5m]))`
+	// HwmonNodePowerQuery is each node's power in watts as reported by hwmon.
+	HwmonNodePowerQuery = `sum by (node) (node_hwmon_power_average_watt)`
+)
+
+// MeasuredPowerQuery returns the default query for a measured power source:
+// kepler or hwmon.
+func MeasuredPowerQuery(source string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(source)) {
+	case "kepler":
+		return KeplerNodePowerQuery, nil
+	case "hwmon":
+		return HwmonNodePowerQuery, nil
+	default:
+		return "", fmt.Errorf("invalid measured power source %q (expected: kepler or hwmon)", source)
+	}
+}
+
+// NodeSeries runs query over the window and returns per-node aggregates keyed
+// by NodeLabel.
+func (c *Client) NodeSeries(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[string]Aggregate, error) {
+	resp, err := c.QueryRange(ctx, query, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	return AggregateSeriesBy(resp, NodeLabel), nil
+}
@@ -0,0 +1,28 @@
+package stats
+
+import "testing"
+
+func TestAggregateSeriesBy(t *testing.T) {
+	resp := &QueryRangeResponse{Status: "success"}
+	resp.Data.Result = []MatrixSeries{
+		matrixSeries(map[string]string{"node": "nuc-1"}, "10", "20", "30"),
+		matrixSeries(map[string]string{"instance": "10.0.0.1:9100"}, "5"),
+	}
+
+	got := AggregateSeriesBy(resp, NodeLabel)
+	if len(got) != 1 {
+		t.Fatalf("expected only the series with a node label, got %+v", got)
+	}
+	if agg := got["nuc-1"]; agg.Avg != 20 || agg.Samples != 3 {
+		t.Fatalf("expected avg 20 over 3 samples, got %+v", agg)
+	}
+}
+
+func TestMeasuredPowerQuery(t *testing.T) {
+	if q, err := MeasuredPowerQuery("Kepler"); err != nil || q != KeplerNodePowerQuery {
+		t.Fatalf("expected kepler query, got %q (%v)", q, err)
+	}
+	if _, err := MeasuredPowerQuery("ipmi"); err == nil {
+		t.Fatalf("expected error for unknown power source")
+	}
+}
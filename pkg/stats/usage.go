@@ -0,0 +1,241 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	cpuUsageQueryTemplate    = `sum by (%s) (rate(container_cpu_usage_seconds_total{container!="",pod!=""}[5m]))`
+	memoryUsageQueryTemplate = `sum by (%s) (container_memory_working_set_bytes{container!="",pod!=""})`
+)
+
+// UsageLevel selects the label set usage series are grouped by.
+type UsageLevel int
+
+const (
+	LevelNamespace UsageLevel = iota
+	LevelPod
+	LevelContainer
+)
+
+// ParseUsageLevel maps namespace, pod or container to a UsageLevel.
+func ParseUsageLevel(s string) (UsageLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "namespace":
+		return LevelNamespace, nil
+	case "pod":
+		return LevelPod, nil
+	case "container":
+		return LevelContainer, nil
+	default:
+		return 0, fmt.Errorf("invalid usage level %q (expected: namespace, pod or container)", s)
+	}
+}
+
+func (l UsageLevel) labels() string {
+	switch l {
+	case LevelPod:
+		return "namespace, pod"
+	case LevelContainer:
+		return "namespace, pod, container"
+	default:
+		return "namespace"
+	}
+}
+
+// CPUUsageQuery returns the PromQL for CPU cores grouped at level.
+func CPUUsageQuery(level UsageLevel) string {
+	return fmt.Sprintf(cpuUsageQueryTemplate, level.labels())
+}
+
+// MemoryUsageQuery returns the PromQL for working-set bytes grouped at level.
+func MemoryUsageQuery(level UsageLevel) string {
+	return fmt.Sprintf(memoryUsageQueryTemplate, level.labels())
+}
+
+// PodKey identifies a pod by namespace and name, matching the labels
+// Prometheus attaches to cAdvisor series.
+type PodKey struct {
+	Namespace string
+	Name      string
+}
+
+// UsageKey identifies a usage series. Fields below the query level are empty.
+type UsageKey struct {
+	Namespace string
+	Pod       string
+	Container string
+}
+
+// PodKey returns the pod identity of k.
+func (k UsageKey) PodKey() PodKey {
+	return PodKey{Namespace: k.Namespace, Name: k.Pod}
+}
+
+func (k UsageKey) String() string {
+	parts := []string{k.Namespace}
+	if k.Pod != "" {
+		parts = append(parts, k.Pod)
+	}
+	if k.Container != "" {
+		parts = append(parts, k.Container)
+	}
+	return strings.Join(parts, "/")
+}
+
+// Aggregate summarizes the samples of one series.
+type Aggregate struct {
+	Avg     float64
+	Max     float64
+	P50     float64
+	P95     float64
+	P99     float64
+	Samples int
+}
+
+// Usage holds CPU (cores) and memory (working-set bytes) aggregates for one
+// series key.
+type Usage struct {
+	CPU    Aggregate
+	Memory Aggregate
+}
+
+// Usage runs the CPU and memory range queries grouped at level and returns
+// per-series aggregates.
+func (c *Client) Usage(ctx context.Context, level UsageLevel, start, end time.Time, step time.Duration) (map[UsageKey]Usage, error) {
+	cpuResp, err := c.QueryRange(ctx, CPUUsageQuery(level), start, end, step)
+	if err != nil {
+		return nil, fmt.Errorf("query cpu usage: %w", err)
+	}
+	memResp, err := c.QueryRange(ctx, MemoryUsageQuery(level), start, end, step)
+	if err != nil {
+		return nil, fmt.Errorf("query memory usage: %w", err)
+	}
+
+	out := make(map[UsageKey]Usage)
+	for key, agg := range AggregateSeries(cpuResp) {
+		u := out[key]
+		u.CPU = agg
+		out[key] = u
+	}
+	for key, agg := range AggregateSeries(memResp) {
+		u := out[key]
+		u.Memory = agg
+		out[key] = u
+	}
+	return out, nil
+}
+
+// PodUsage is Usage at LevelPod keyed by pod identity so callers can join it
+// to corev1.Pod objects.
+func (c *Client) PodUsage(ctx context.Context, start, end time.Time, step time.Duration) (map[PodKey]Usage, error) {
+	byKey, err := c.Usage(ctx, LevelPod, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[PodKey]Usage, len(byKey))
+	for key, u := range byKey {
+		out[key.PodKey()] = u
+	}
+	return out, nil
+}
+
+// AggregateSeries computes avg, max and percentiles for every series in resp,
+// keyed by its namespace, pod and container labels. Series without parseable
+// samples are omitted.
+func AggregateSeries(resp *QueryRangeResponse) map[UsageKey]Aggregate {
+	out := make(map[UsageKey]Aggregate)
+	if resp == nil {
+		return out
+	}
+
+	for _, series := range resp.Data.Result {
+		key := UsageKey{
+			Namespace: series.Metric["namespace"],
+			Pod:       series.Metric["pod"],
+			Container: series.Metric["container"],
+		}
+		if values := seriesValues(series); len(values) > 0 {
+			out[key] = aggregate(values)
+		}
+	}
+	return out
+}
+
+// AggregateSeriesBy is AggregateSeries keyed by a single label. Series
+// without the label or without parseable samples are omitted.
+func AggregateSeriesBy(resp *QueryRangeResponse, label string) map[string]Aggregate {
+	out := make(map[string]Aggregate)
+	if resp == nil {
+		return out
+	}
+
+	for _, series := range resp.Data.Result {
+		key := series.Metric[label]
+		if key == "" {
+			continue
+		}
+		if values := seriesValues(series); len(values) > 0 {
+			out[key] = aggregate(values)
+		}
+	}
+	return out
+}
+
+// seriesValues returns the parseable, non-NaN samples of series.
+func seriesValues(series MatrixSeries) []float64 {
+	values := make([]float64, 0, len(series.Values))
+	for _, point := range series.Values {
+		if len(point) < 2 {
+			continue
+		}
+		raw, ok := point[1].(string)
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(v) {
+			continue
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+func aggregate(values []float64) Aggregate {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	return Aggregate{
+		Avg:     sum / float64(len(sorted)),
+		Max:     sorted[len(sorted)-1],
+		P50:     percentile(sorted, 0.50),
+		P95:     percentile(sorted, 0.95),
+		P99:     percentile(sorted, 0.99),
+		Samples: len(sorted),
+	}
+}
+
+// percentile linearly interpolates the q-th quantile of sorted values.
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
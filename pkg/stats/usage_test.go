@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func matrixSeries(metric map[string]string, values ...string) MatrixSeries {
+	s := MatrixSeries{Metric: metric}
+	for i, v := range values {
+		s.Values = append(s.Values, []interface{}{float64(1700000000 + i*60), v})
+	}
+	return s
+}
+
+func TestAggregateSeries(t *testing.T) {
+	resp := &QueryRangeResponse{Status: "success"}
+	resp.Data.Result = []MatrixSeries{
+		matrixSeries(map[string]string{"namespace": "shop", "pod": "checkout-1", "container": "app"},
+			"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "NaN", "bogus"),
+		matrixSeries(map[string]string{"namespace": "shop", "pod": "checkout-2", "container": "app"}),
+	}
+
+	got := AggregateSeries(resp)
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 series with samples, got %d", len(got))
+	}
+	agg, ok := got[UsageKey{Namespace: "shop", Pod: "checkout-1", Container: "app"}]
+	if !ok {
+		t.Fatalf("expected checkout-1/app series, got %+v", got)
+	}
+	if agg.Samples != 10 {
+		t.Fatalf("expected 10 samples, got %d", agg.Samples)
+	}
+	checks := map[string][2]float64{
+		"avg": {agg.Avg, 5.5},
+		"max": {agg.Max, 10},
+		"p50": {agg.P50, 5.5},
+		"p95": {agg.P95, 9.55},
+		"p99": {agg.P99, 9.91},
+	}
+	for name, c := range checks {
+		if math.Abs(c[0]-c[1]) > 1e-9 {
+			t.Fatalf("expected %s=%.4f, got %.4f", name, c[1], c[0])
+		}
+	}
+}
+
+func TestUsageKeyPodKey(t *testing.T) {
+	key := UsageKey{Namespace: "shop", Pod: "checkout-1", Container: "app"}
+	if got := key.PodKey(); got != (PodKey{Namespace: "shop", Name: "checkout-1"}) {
+		t.Fatalf("unexpected pod key %+v", got)
+	}
+	if got := key.String(); got != "shop/checkout-1/app" {
+		t.Fatalf("unexpected key string %s", got)
+	}
+}
+
+func TestParseUsageLevel(t *testing.T) {
+	if l, err := ParseUsageLevel("Container"); err != nil || l != LevelContainer {
+		t.Fatalf("expected container level, got %v (%v)", l, err)
+	}
+	if _, err := ParseUsageLevel("node"); err == nil {
+		t.Fatalf("expected error for unsupported level")
+	}
+	if got := CPUUsageQuery(LevelPod); got != `sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!="",pod!=""}[5m]))` {
+		t.Fatalf("unexpected pod cpu query %s", got)
+	}
+}
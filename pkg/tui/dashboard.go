@@ -7,36 +7,35 @@ import (
 	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/newman-bot/kfin/pkg/cost"
 	"github.com/rivo/tview"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type PodInfo struct {
-	Name      string
-	Namespace string
-	CPU       string
-	Memory    string
-	Cost      float64
-}
-
-type NodeInfo struct {
-	Name         string
-	MemoryGB     float64
-	HardwareCost float64
-	ElecCost     float64
-	TotalCost    float64
+	Name        string
+	Namespace   string
+	Workload    string
+	CPU         string
+	Memory      string
+	Cost        float64
+	Basis       string
+	Requests    cost.PodRequests
+	Extended    corev1.ResourceList
+	Network     cost.NetworkTraffic
+	NetworkCost float64
 }
 
 type ReportData struct {
-	PodCosts         []PodInfo
-	TotalCost        float64
-	HardwareCost     float64
-	ElecCost         float64
-	ControlPlaneCost float64
-	Nodes            []NodeInfo
-	ContextName      string
-	ClusterName      string
-	PricingSource    string
-	StatsFreshness   StatsFreshness
+	Report         *cost.Report
+	ContextName    string
+	ClusterName    string
+	StatsFreshness StatsFreshness
+	// Usage cost forecast from Prometheus history; nil when unavailable.
+	Forecast *cost.Forecast
+	// GroupBy adds a cost table grouped by these dimensions to the
+	// allocation page when set.
+	GroupBy []cost.GroupDimension
 }
 
 type StatsFreshness struct {
@@ -53,15 +52,33 @@ const (
 	pageOverview   = "1"
 	pageNamespaces = "2"
 	pageNodes      = "3"
+	pageWorkloads  = "4"
+	pageFindings   = "5"
+	pageAllocation = "6"
 )
 
 func ShowDashboard(data ReportData) {
 	app := tview.NewApplication()
+	if err := app.SetRoot(newDashboard(app, data, nil), true).Run(); err != nil {
+		fmt.Printf("Error running TUI: %v\n", err)
+	}
+}
+
+// newDashboard builds the dashboard for one cluster. When showClusters is
+// set, the dashboard is part of a fleet and [7] returns to the cluster
+// switcher through it.
+func newDashboard(app *tview.Application, data ReportData, showClusters func()) tview.Primitive {
 	pages := tview.NewPages()
-	namespaces := getNamespaces(data.PodCosts)
+	report := data.Report
+	podCosts := buildPodInfo(report)
 	// Sort namespaces alphabetically
+	namespaces := namespaceNames(report.Namespaces)
 	sort.Strings(namespaces)
-	nsInfo := buildNamespaceInfo(data.PodCosts)
+	nsInfo := buildNamespaceInfo(podCosts)
+	nsOwners := make(map[string]cost.NamespaceCost, len(report.Namespaces))
+	for _, ns := range report.Namespaces {
+		nsOwners[ns.Name] = ns
+	}
 	nsIndex := make(map[string]int, len(namespaces))
 	for i, ns := range namespaces {
 		nsIndex[ns] = i
@@ -74,14 +91,22 @@ func ShowDashboard(data ReportData) {
 	headerBar.SetDirection(tview.FlexRow).SetBorder(false).SetBackgroundColor(tcell.ColorBlack)
 
 	headerTop := fmt.Sprintf(
-		"kFin | Context: %s | Cluster: %s | Nodes:%d | Monthly:$%.2f | Rates:%s",
+		"kFin | Context: %s | Cluster: %s | Dist:%s | Nodes:%d | Monthly:$%.2f%s | Rates:%s | Basis:%s | Alloc:%s",
 		truncateString(data.ContextName, 28),
 		truncateString(data.ClusterName, 28),
-		len(data.Nodes),
-		data.TotalCost,
-		truncateString(data.PricingSource, 12),
+		report.Distribution,
+		len(report.Nodes),
+		report.TotalCost,
+		forecastHeaderText(data.Forecast),
+		truncateString(report.PricingSource, 12),
+		report.CostBasis,
+		report.Allocation,
 	)
-	headerMid := " [1] Overview  [2] Namespaces  [3] Nodes "
+	clustersNav := ""
+	if showClusters != nil {
+		clustersNav = "  [7] Clusters"
+	}
+	headerMid := " [1] Overview  [2] Namespaces  [3] Nodes  [4] Workloads  [5] Findings  [6] Allocation" + clustersNav + " "
 
 	logoView := tview.NewTextView().
 		SetText(buildASCIIKFinLogo()).
@@ -102,34 +127,60 @@ func ShowDashboard(data ReportData) {
 	overview := tview.NewFlex().SetDirection(tview.FlexRow)
 	snapshot := tview.NewTextView().SetDynamicColors(true)
 	snapshot.SetBorder(true).SetTitle(" Cluster Snapshot ").SetTitleColor(cyan)
-	tierLabel, tierColor := costTier(data.TotalCost)
+	tierLabel, tierColor := costTier(report.TotalCost)
+	var highFindings int
+	var findingsImpact float64
+	for _, f := range report.Findings {
+		if f.Severity == cost.SeverityHigh {
+			highFindings++
+		}
+		findingsImpact += f.MonthlyImpact
+	}
 	snapshot.SetText(fmt.Sprintf(
-		" Pods:        %d\n Nodes:       %d\n Namespaces:  %d\n Monthly:     $%.2f\n Daily:       $%.2f\n Cost Tier:   [%s]%s[-]",
-		len(data.PodCosts),
-		len(data.Nodes),
+		" Pods:        %d\n Nodes:       %d\n Namespaces:  %d\n Monthly:     $%.2f\n Daily:       $%.2f\n Cost Tier:   [%s]%s[-]\n Findings:    %d ([red]%d high[-], $%.2f at stake, [5])",
+		len(podCosts),
+		len(report.Nodes),
 		len(namespaces),
-		data.TotalCost,
-		data.TotalCost/30.0,
+		report.TotalCost,
+		report.TotalCost/30.0,
 		tierColor,
 		tierLabel,
+		len(report.Findings),
+		highFindings,
+		findingsImpact,
 	))
 
-	var hardwarePct, elecPct, controlPlanePct float64
-	if data.TotalCost > 0 {
-		hardwarePct = (data.HardwareCost / data.TotalCost) * 100.0
-		elecPct = (data.ElecCost / data.TotalCost) * 100.0
-		controlPlanePct = (data.ControlPlaneCost / data.TotalCost) * 100.0
+	var hardwarePct, elecPct, controlPlanePct, storagePct, lbPct, networkPct, fargatePct, idlePct float64
+	if report.TotalCost > 0 {
+		hardwarePct = (report.HardwareCost / report.TotalCost) * 100.0
+		elecPct = (report.ElecCost / report.TotalCost) * 100.0
+		controlPlanePct = (report.ControlPlaneCost / report.TotalCost) * 100.0
+		storagePct = (report.StorageCost / report.TotalCost) * 100.0
+		lbPct = (report.LoadBalancerCost / report.TotalCost) * 100.0
+		networkPct = (report.NetworkCost / report.TotalCost) * 100.0
+		fargatePct = (report.FargateCost / report.TotalCost) * 100.0
+		idlePct = (report.IdleCost / report.TotalCost) * 100.0
 	}
 	costBreakdown := tview.NewTextView().SetDynamicColors(true)
 	costBreakdown.SetBorder(true).SetTitle(" Cost Breakdown ").SetTitleColor(cyan)
 	costBreakdown.SetText(fmt.Sprintf(
-		" Hardware:      $%.2f (%.1f%%)\n Electricity:   $%.2f (%.1f%%)\n Control Plane: $%.2f (%.1f%%)\n Allocation:\n [green]H[-] %s\n [yellow]E[-] %s\n [blue]C[-] %s",
-		data.HardwareCost, hardwarePct,
-		data.ElecCost, elecPct,
-		data.ControlPlaneCost, controlPlanePct,
+		" Hardware:      $%.2f (%.1f%%)\n Electricity:   $%.2f (%.1f%%)\n Control Plane: $%.2f (%.1f%%)\n Storage:       $%.2f (%.1f%%)\n Load Bal.:     $%.2f (%.1f%%)\n Network:       $%.2f (%.1f%%)\n Fargate:       $%.2f (%.1f%%)\n Idle (of H+E): $%.2f (%.1f%%)\n Allocation:\n [green]H[-] %s\n [yellow]E[-] %s\n [blue]C[-] %s\n [purple]S[-] %s\n [aqua]L[-] %s\n [orange]N[-] %s\n [fuchsia]F[-] %s\n [red]I[-] %s",
+		report.HardwareCost, hardwarePct,
+		report.ElecCost, elecPct,
+		report.ControlPlaneCost, controlPlanePct,
+		report.StorageCost, storagePct,
+		report.LoadBalancerCost, lbPct,
+		report.NetworkCost, networkPct,
+		report.FargateCost, fargatePct,
+		report.IdleCost, idlePct,
 		renderCostBar(hardwarePct),
 		renderCostBar(elecPct),
 		renderCostBar(controlPlanePct),
+		renderCostBar(storagePct),
+		renderCostBar(lbPct),
+		renderCostBar(networkPct),
+		renderCostBar(fargatePct),
+		renderCostBar(idlePct),
 	))
 
 	topPods := tview.NewTable().SetBorders(false)
@@ -140,7 +191,7 @@ func ShowDashboard(data ReportData) {
 	for i, h := range topPodsHeaders {
 		topPods.SetCell(0, i, tview.NewTableCell(h).SetTextColor(cyan).SetAlign(tview.AlignLeft))
 	}
-	topPodItems := topPodsByCost(data.PodCosts, 8)
+	topPodItems := topPodsByCost(podCosts, 8)
 	for i, pod := range topPodItems {
 		topPods.SetCell(i+1, 0, tview.NewTableCell(truncateString(pod.Name, 28)).SetAlign(tview.AlignLeft))
 		topPods.SetCell(i+1, 1, tview.NewTableCell(truncateString(pod.Namespace, 16)).SetAlign(tview.AlignLeft))
@@ -158,7 +209,7 @@ func ShowDashboard(data ReportData) {
 	for i, h := range topNSHeaders {
 		topNS.SetCell(0, i, tview.NewTableCell(h).SetTextColor(cyan).SetAlign(tview.AlignLeft))
 	}
-	topNSItems := topNamespacesByCost(nsInfo, 8)
+	topNSItems := topNamespacesByCost(report.Namespaces, 8)
 	for i, ns := range topNSItems {
 		topNS.SetCell(i+1, 0, tview.NewTableCell(truncateString(ns.name, 28)).SetAlign(tview.AlignLeft))
 		topNS.SetCell(i+1, 1, tview.NewTableCell(fmt.Sprintf("%d", ns.count)).SetAlign(tview.AlignRight))
@@ -176,7 +227,7 @@ func ShowDashboard(data ReportData) {
 	bottomRow.AddItem(topPods, 0, 1, false)
 	bottomRow.AddItem(topNS, 0, 1, false)
 
-	overview.AddItem(topRow, 8, 0, false)
+	overview.AddItem(topRow, 19, 0, false)
 	overview.AddItem(bottomRow, 0, 1, false)
 	activeOverviewTable := 0
 	updateOverviewFocus := func() {
@@ -194,10 +245,34 @@ func ShowDashboard(data ReportData) {
 	nodesView := tview.NewFlex().SetDirection(tview.FlexRow)
 	nodesList := tview.NewTextView().
 		SetDynamicColors(true).
-		SetText(buildNodesListText(data.Nodes))
+		SetText(buildNodesListText(report))
 	nodesList.SetBorder(false)
 	nodesView.AddItem(nodesList, 0, 1, false)
 
+	// ========== WORKLOADS VIEW ==========
+	workloadsView := tview.NewFlex().SetDirection(tview.FlexRow)
+	workloadsList := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(buildWorkloadsListText(report.Workloads))
+	workloadsList.SetBorder(false)
+	workloadsView.AddItem(workloadsList, 0, 1, false)
+
+	// ========== FINDINGS VIEW ==========
+	findingsView := tview.NewFlex().SetDirection(tview.FlexRow)
+	findingsList := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(buildFindingsListText(report.Findings))
+	findingsList.SetBorder(false)
+	findingsView.AddItem(findingsList, 0, 1, false)
+
+	// ========== ALLOCATION VIEW ==========
+	allocationView := tview.NewFlex().SetDirection(tview.FlexRow)
+	allocationList := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(buildAllocationListText(report, data.GroupBy))
+	allocationList.SetBorder(false)
+	allocationView.AddItem(allocationList, 0, 1, false)
+
 	// ========== BY NAMESPACE VIEW ==========
 	nsView := tview.NewFlex().SetDirection(tview.FlexRow)
 
@@ -209,7 +284,7 @@ func ShowDashboard(data ReportData) {
 		nsPage := tview.NewFlex().SetDirection(tview.FlexRow)
 		nsList := tview.NewTextView().
 			SetDynamicColors(true).
-			SetText(buildNamespaceListText(nsPods, true))
+			SetText(buildNamespaceListText(nsPods, nsOwners[ns], report.ExtendedResources, true))
 		nsList.SetBorder(false)
 		nsPage.AddItem(nsList, 0, 1, false)
 		nsPages.AddPage(fmt.Sprintf("%d", i), nsPage, true, false)
@@ -220,12 +295,18 @@ func ShowDashboard(data ReportData) {
 	pages.AddPage(pageOverview, overview, true, true)
 	pages.AddPage(pageNamespaces, nsView, true, false)
 	pages.AddPage(pageNodes, nodesView, true, false)
+	pages.AddPage(pageWorkloads, workloadsView, true, false)
+	pages.AddPage(pageFindings, findingsView, true, false)
+	pages.AddPage(pageAllocation, allocationView, true, false)
 
 	updateHeaderNav := func() {
 		currentPage, _ := pages.GetFrontPage()
 		overviewLabel := "[1] Overview"
 		nsLabel := "[2] Namespaces"
 		nodesLabel := "[3] Nodes"
+		workloadsLabel := "[4] Workloads"
+		findingsLabel := "[5] Findings"
+		allocationLabel := "[6] Allocation"
 		switch currentPage {
 		case pageOverview:
 			overviewLabel = "[darkcyan][1] Overview[-]"
@@ -233,8 +314,14 @@ func ShowDashboard(data ReportData) {
 			nsLabel = "[darkcyan][2] Namespaces[-]"
 		case pageNodes:
 			nodesLabel = "[darkcyan][3] Nodes[-]"
+		case pageWorkloads:
+			workloadsLabel = "[darkcyan][4] Workloads[-]"
+		case pageFindings:
+			findingsLabel = "[darkcyan][5] Findings[-]"
+		case pageAllocation:
+			allocationLabel = "[darkcyan][6] Allocation[-]"
 		}
-		headerMidView.SetText(fmt.Sprintf(" %s  %s  %s ", overviewLabel, nsLabel, nodesLabel))
+		headerMidView.SetText(fmt.Sprintf(" %s  %s  %s  %s  %s  %s%s ", overviewLabel, nsLabel, nodesLabel, workloadsLabel, findingsLabel, allocationLabel, clustersNav))
 	}
 	updateHeaderNav()
 
@@ -252,15 +339,20 @@ func ShowDashboard(data ReportData) {
 		case pageOverview:
 			pageTitleView.SetText(" [darkcyan]OVERVIEW[-]  |  [gray]Tab/Left/Right switch tables, Up/Down move row, Enter pod details[-]")
 		case pageNodes:
-			pageTitleView.SetText(" [darkcyan]NODES[-]  |  [gray]Cluster monthly hardware + electricity by node[-]")
+			pageTitleView.SetText(" [darkcyan]NODES[-]  |  [gray]Cluster monthly hardware + electricity by node, idle = unrequested share[-]")
+		case pageWorkloads:
+			pageTitleView.SetText(" [darkcyan]WORKLOADS[-]  |  [gray]Pod costs rolled up to Deployments, StatefulSets, DaemonSets and Jobs[-]")
+		case pageFindings:
+			pageTitleView.SetText(" [darkcyan]FINDINGS[-]  |  [gray]Waste and hygiene problems by severity, with estimated monthly impact[-]")
+		case pageAllocation:
+			pageTitleView.SetText(" [darkcyan]ALLOCATION[-]  |  [gray]Costs by --group-by and the --chargeback invoice[-]")
 		case pageNamespaces:
 			if len(namespaces) == 0 {
 				pageTitleView.SetText(" [darkcyan]NAMESPACES[-]")
 				return
 			}
 			ns := namespaces[currentNS]
-			info := nsInfo[ns]
-			pageTitleView.SetText(fmt.Sprintf(" [darkcyan]NAMESPACES[-]  >  [white]%s[-]  |  Pods:%d  Cost:$%.2f", ns, info.count, info.cost))
+			pageTitleView.SetText(fmt.Sprintf(" [darkcyan]NAMESPACES[-]  >  [white]%s[-]  |  Pods:%d  Cost:$%.2f  |  %s", ns, nsOwners[ns].Pods, nsOwners[ns].Cost, namespaceOwnerText(nsOwners[ns], report)))
 		}
 	}
 	updatePageTitle()
@@ -280,7 +372,7 @@ func ShowDashboard(data ReportData) {
 			AddItem(tview.NewBox(), 0, 1, false).
 			AddItem(podDetailView, 80, 0, true).
 			AddItem(tview.NewBox(), 0, 1, false),
-		12, 0, true,
+		21, 0, true,
 	)
 	podModalFrame.AddItem(tview.NewBox(), 0, 1, false)
 	const pagePodDetail = "pod-detail"
@@ -305,6 +397,9 @@ func ShowDashboard(data ReportData) {
 		overviewLabel := "[1] Overview"
 		nsLabel := "[2] Namespaces"
 		nodesLabel := "[3] Nodes"
+		workloadsLabel := "[4] Workloads"
+		findingsLabel := "[5] Findings"
+		allocationLabel := "[6] Allocation"
 		switch currentPage {
 		case pageOverview:
 			overviewLabel = "[darkcyan][1] Overview[-]"
@@ -312,8 +407,14 @@ func ShowDashboard(data ReportData) {
 			nsLabel = "[darkcyan][2] Namespaces[-]"
 		case pageNodes:
 			nodesLabel = "[darkcyan][3] Nodes[-]"
+		case pageWorkloads:
+			workloadsLabel = "[darkcyan][4] Workloads[-]"
+		case pageFindings:
+			findingsLabel = "[darkcyan][5] Findings[-]"
+		case pageAllocation:
+			allocationLabel = "[darkcyan][6] Allocation[-]"
 		}
-		footerNavView.SetText(fmt.Sprintf(" %s  %s  %s  |  Left/Right: Cycle NS  Esc: Back  : Command ", overviewLabel, nsLabel, nodesLabel))
+		footerNavView.SetText(fmt.Sprintf(" %s  %s  %s  %s  %s  %s%s  |  Left/Right: Cycle NS  Esc: Back  : Command ", overviewLabel, nsLabel, nodesLabel, workloadsLabel, findingsLabel, allocationLabel, clustersNav))
 	}
 	updateFooterNav()
 
@@ -407,6 +508,17 @@ func ShowDashboard(data ReportData) {
 			switchToPage(pageNamespaces)
 		case "3":
 			switchToPage(pageNodes)
+		case "4":
+			switchToPage(pageWorkloads)
+		case "5":
+			switchToPage(pageFindings)
+		case "6":
+			switchToPage(pageAllocation)
+		case "7":
+			if showClusters != nil {
+				showClusters()
+				return nil
+			}
 		case ":":
 			commandMode = true
 			commandBuffer = ":"
@@ -504,9 +616,7 @@ func ShowDashboard(data ReportData) {
 		return event
 	})
 
-	if err := app.SetRoot(mainLayout, true).Run(); err != nil {
-		fmt.Printf("Error running TUI: %v\n", err)
-	}
+	return mainLayout
 }
 
 func buildASCIIKFinLogo() string {
@@ -518,9 +628,11 @@ func buildASCIIKFinLogo() string {
 }
 
 type nsCostInfo struct {
-	count int
-	cost  float64
-	pods  []PodInfo
+	count       int
+	cost        float64
+	pods        []PodInfo
+	network     cost.NetworkTraffic
+	networkCost float64
 }
 
 type nsSummary struct {
@@ -529,6 +641,26 @@ type nsSummary struct {
 	cost  float64
 }
 
+func buildPodInfo(report *cost.Report) []PodInfo {
+	result := make([]PodInfo, 0, len(report.Pods))
+	for _, pod := range report.Pods {
+		result = append(result, PodInfo{
+			Name:        pod.Name,
+			Namespace:   pod.Namespace,
+			Workload:    pod.Workload.String(),
+			CPU:         pod.CPU.String(),
+			Memory:      pod.Memory.String(),
+			Cost:        pod.Cost,
+			Basis:       string(pod.Basis),
+			Requests:    pod.Requests,
+			Extended:    pod.Extended,
+			Network:     pod.Network,
+			NetworkCost: pod.NetworkCost,
+		})
+	}
+	return result
+}
+
 func buildNamespaceInfo(pods []PodInfo) map[string]nsCostInfo {
 	nsInfo := make(map[string]nsCostInfo)
 	for _, pod := range pods {
@@ -536,11 +668,47 @@ func buildNamespaceInfo(pods []PodInfo) map[string]nsCostInfo {
 		info.count++
 		info.cost += pod.Cost
 		info.pods = append(info.pods, pod)
+		info.network.InternetGB += pod.Network.InternetGB
+		info.network.CrossZoneGB += pod.Network.CrossZoneGB
+		info.network.InZoneGB += pod.Network.InZoneGB
+		info.networkCost += pod.NetworkCost
 		nsInfo[pod.Namespace] = info
 	}
 	return nsInfo
 }
 
+// forecastHeaderText renders the forecast's end-of-month and next-month
+// projections for the header, or nothing without a forecast. The forecast is
+// scaled to the report's TotalCost, so it reads against Monthly.
+func forecastHeaderText(f *cost.Forecast) string {
+	if f == nil {
+		return ""
+	}
+	eom, next := f.EndOfMonth, f.NextMonth
+	return fmt.Sprintf(" | Fcst %s:$%.0f ($%.0f-$%.0f) %s:$%.0f ($%.0f-$%.0f)",
+		eom.Month.Format("Jan"), eom.Cost, eom.Low, eom.High,
+		next.Month.Format("Jan"), next.Cost, next.Low, next.High)
+}
+
+// namespaceOwnerText renders a namespace's ownership annotations for the
+// namespace header, flagging a missing owner, with the budget's burn colored
+// by its status.
+func namespaceOwnerText(ns cost.NamespaceCost, report *cost.Report) string {
+	owner := "[red]none[-]"
+	if ns.Owner != "" {
+		owner = ns.Owner
+	}
+	costCenter := "-"
+	if ns.CostCenter != "" {
+		costCenter = ns.CostCenter
+	}
+	budget := "-"
+	if b, ok := report.NamespaceBudget(ns.Name); ok {
+		budget = fmt.Sprintf("$%.2f ([%s]%.0f%%[-])", b.Limit, budgetColor(b.Status), b.Burn*100)
+	}
+	return fmt.Sprintf("Owner:%s  Cost center:%s  Budget:%s", owner, costCenter, budget)
+}
+
 func topPodsByCost(pods []PodInfo, n int) []PodInfo {
 	var filtered []PodInfo
 	for _, pod := range pods {
@@ -557,10 +725,15 @@ func topPodsByCost(pods []PodInfo, n int) []PodInfo {
 	return filtered
 }
 
-func topNamespacesByCost(nsInfo map[string]nsCostInfo, n int) []nsSummary {
-	summaries := make([]nsSummary, 0, len(nsInfo))
-	for name, info := range nsInfo {
-		summaries = append(summaries, nsSummary{name: name, count: info.count, cost: info.cost})
+// topNamespacesByCost ranks namespaces by their rollup cost, which includes
+// unmounted volumes and load balancers on top of their pods.
+func topNamespacesByCost(namespaces []cost.NamespaceCost, n int) []nsSummary {
+	summaries := make([]nsSummary, 0, len(namespaces))
+	for _, ns := range namespaces {
+		if ns.Name == cost.IdleNamespace {
+			continue
+		}
+		summaries = append(summaries, nsSummary{name: ns.Name, count: ns.Pods, cost: ns.Cost})
 	}
 	sort.Slice(summaries, func(i, j int) bool {
 		return summaries[i].cost > summaries[j].cost
@@ -623,24 +796,37 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
-func buildNamespaceListText(nsPods nsCostInfo, hideZeroCost bool) string {
+// buildNamespaceListText lists a namespace's pods with their total, and
+// notes the part of the namespace's cost that no pod carries.
+func buildNamespaceListText(nsPods nsCostInfo, nsCost cost.NamespaceCost, extended []corev1.ResourceName, hideZeroCost bool) string {
 	const leftPad = "  "
-	header := fmt.Sprintf("[darkcyan]%-30s %10s %10s %12s[-]", "NAME", "CPU", "MEM", "COST")
-	separator := "--------------------------------------------------------------------"
+	extHeader := ""
+	for _, name := range extended {
+		extHeader += fmt.Sprintf(" %14s", truncateString(string(name), 14))
+	}
+	header := fmt.Sprintf("[darkcyan]%-30s %10s %10s%s %12s[-]", "NAME", "CPU", "MEM", extHeader, "COST")
+	separator := "--------------------------------------------------------------------" + strings.Repeat("-", len(extHeader))
 	lines := []string{leftPad + header, leftPad + separator}
 
 	pods := append([]PodInfo(nil), nsPods.pods...)
 	sort.Slice(pods, func(i, j int) bool { return pods[i].Cost > pods[j].Cost })
 	rowCount := 0
+	extTotal := corev1.ResourceList{}
 	for _, pod := range pods {
+		for name, q := range pod.Extended {
+			total := extTotal[name]
+			total.Add(q)
+			extTotal[name] = total
+		}
 		if hideZeroCost && pod.Cost == 0 {
 			continue
 		}
 		lines = append(lines, leftPad+fmt.Sprintf(
-			"%-30s %10s %10s %12s",
+			"%-30s %10s %10s%s %12s",
 			truncateString(pod.Name, 30),
 			pod.CPU,
 			pod.Memory,
+			formatExtended(extended, pod.Extended),
 			fmt.Sprintf("$%.2f", pod.Cost),
 		))
 		rowCount++
@@ -650,41 +836,257 @@ func buildNamespaceListText(nsPods nsCostInfo, hideZeroCost bool) string {
 	}
 
 	lines = append(lines, leftPad+separator)
-	lines = append(lines, leftPad+fmt.Sprintf("[green]%-30s %10s %10s %12s[-]", "TOTAL", "", "", fmt.Sprintf("$%.2f", nsPods.cost)))
+	lines = append(lines, leftPad+fmt.Sprintf("[green]%-30s %10s %10s%s %12s[-]", "TOTAL", "", "", formatExtended(extended, extTotal), fmt.Sprintf("$%.2f", nsPods.cost)))
+	if nsPods.networkCost > 0 {
+		lines = append(lines, "", leftPad+fmt.Sprintf(
+			"[darkcyan]Network:[-] $%.2f  (internet %.1f GB, cross-zone %.1f GB, in-zone %.1f GB per month, included above)",
+			nsPods.networkCost, nsPods.network.InternetGB, nsPods.network.CrossZoneGB, nsPods.network.InZoneGB,
+		))
+	}
+	if outside := nsCost.Cost - nsPods.cost; outside > 0.005 {
+		lines = append(lines, "", leftPad+fmt.Sprintf(
+			"[darkcyan]Outside pods:[-] $%.2f  (unmounted volumes and load balancers, namespace cost $%.2f)",
+			outside, nsCost.Cost,
+		))
+	}
 	return strings.Join(lines, "\n")
 }
 
-func buildNodesListText(nodes []NodeInfo) string {
+// formatExtended renders one right-aligned cell per extended resource name.
+func formatExtended(names []corev1.ResourceName, rl corev1.ResourceList) string {
+	out := ""
+	for _, name := range names {
+		cell := "-"
+		if q, ok := rl[name]; ok && !q.IsZero() {
+			cell = q.String()
+		}
+		out += fmt.Sprintf(" %14s", cell)
+	}
+	return out
+}
+
+func buildNodesListText(report *cost.Report) string {
 	const leftPad = "  "
-	header := fmt.Sprintf("[darkcyan]%-12s %10s %12s %12s %12s[-]", "NODE", "MEMORY", "HARDWARE", "ELECTRICITY", "TOTAL")
-	separator := "--------------------------------------------------------------------------"
+	header := fmt.Sprintf("[darkcyan]%-12s %-9s %10s %10s %12s %12s %12s %12s[-]", "NODE", "CAPACITY", "MEMORY", "WATTS", "HARDWARE", "ELECTRICITY", "TOTAL", "IDLE")
+	separator := "------------------------------------------------------------------------------------------------------------"
 	lines := []string{leftPad + header, leftPad + separator}
-	var total float64
+	var total, idle, watts float64
 
-	for _, node := range nodes {
+	for _, node := range report.Nodes {
+		wattsCell := fmt.Sprintf("%.1fW", node.Watts)
+		if node.PowerSource == cost.PowerMeasured {
+			wattsCell += "*"
+		}
+		capacity := node.CapacityType
+		if capacity == "" {
+			capacity = "-"
+		}
 		lines = append(lines, leftPad+fmt.Sprintf(
-			"%-12s %10s %12s %12s %12s",
+			"%-12s %-9s %10s %10s %12s %12s %12s %12s",
 			truncateString(node.Name, 12),
+			capacity,
 			fmt.Sprintf("%.1fGB", node.MemoryGB),
+			wattsCell,
 			fmt.Sprintf("$%.2f", node.HardwareCost),
 			fmt.Sprintf("$%.2f", node.ElecCost),
 			fmt.Sprintf("$%.2f", node.TotalCost),
+			fmt.Sprintf("$%.2f", node.IdleCost),
 		))
 		total += node.TotalCost
+		idle += node.IdleCost
+		watts += node.Watts
 	}
 	lines = append(lines, leftPad+separator)
-	lines = append(lines, leftPad+fmt.Sprintf("[green]%-12s %10s %12s %12s %12s[-]", "TOTAL", "", "", "", fmt.Sprintf("$%.2f", total)))
+	lines = append(lines, leftPad+fmt.Sprintf("[green]%-12s %-9s %10s %10s %12s %12s %12s %12s[-]", "TOTAL", "", "", fmt.Sprintf("%.1fW", watts), "", "", fmt.Sprintf("$%.2f", total), fmt.Sprintf("$%.2f", idle)))
+	if report.SpotNodes > 0 {
+		lines = append(lines, "", leftPad+fmt.Sprintf("[green]Spot: %d/%d nodes (%.0f%%), saving $%.2f/month vs on-demand[-]",
+			report.SpotNodes, report.InstanceNodes, report.SpotShare*100, report.SpotSavings))
+	}
+	lines = append(lines, "", leftPad+"[gray]* measured power[-]")
 	return strings.Join(lines, "\n")
 }
 
+func buildWorkloadsListText(workloads []cost.WorkloadCost) string {
+	const leftPad = "  "
+	header := fmt.Sprintf("[darkcyan]%-12s %-32s %-18s %6s %12s[-]", "KIND", "WORKLOAD", "NAMESPACE", "PODS", "COST")
+	separator := "------------------------------------------------------------------------------------"
+	lines := []string{leftPad + header, leftPad + separator}
+	var total float64
+
+	rowCount := 0
+	for _, w := range workloads {
+		if w.Cost == 0 {
+			continue
+		}
+		lines = append(lines, leftPad+fmt.Sprintf(
+			"%-12s %-32s %-18s %6d %12s",
+			truncateString(w.Kind, 12),
+			truncateString(w.Name, 32),
+			truncateString(w.Namespace, 18),
+			w.Pods,
+			fmt.Sprintf("$%.2f", w.Cost),
+		))
+		total += w.Cost
+		rowCount++
+	}
+	if rowCount == 0 {
+		lines = append(lines, leftPad+"[gray]No non-zero cost workloads[-]")
+	}
+	lines = append(lines, leftPad+separator)
+	lines = append(lines, leftPad+fmt.Sprintf("[green]%-12s %-32s %-18s %6s %12s[-]", "TOTAL", "", "", "", fmt.Sprintf("$%.2f", total)))
+	return strings.Join(lines, "\n")
+}
+
+func buildFindingsListText(findings []cost.Finding) string {
+	const leftPad = "  "
+	header := fmt.Sprintf("[darkcyan]%-8s %-24s %-18s %-40s %12s[-]", "SEVERITY", "RULE", "NAMESPACE", "OBJECT", "IMPACT")
+	separator := "----------------------------------------------------------------------------------------------------------"
+	lines := []string{leftPad + header, leftPad + separator}
+	var total float64
+	for _, f := range findings {
+		namespace := f.Namespace
+		if namespace == "" {
+			namespace = "-"
+		}
+		lines = append(lines, leftPad+fmt.Sprintf(
+			"[%s]%-8s[-] %-24s %-18s %-40s %12s",
+			severityColor(f.Severity),
+			strings.ToUpper(f.Severity.String()),
+			f.Rule,
+			truncateString(namespace, 18),
+			truncateString(f.Object, 40),
+			fmt.Sprintf("$%.2f", f.MonthlyImpact),
+		))
+		lines = append(lines, leftPad+"         [gray]"+tview.Escape(f.Message)+"[-]")
+		total += f.MonthlyImpact
+	}
+	if len(findings) == 0 {
+		lines = append(lines, leftPad+"[gray]No findings[-]")
+	}
+	lines = append(lines, leftPad+separator)
+	lines = append(lines, leftPad+fmt.Sprintf("[green]%-8s %-24s %-18s %-40s %12s[-]", "TOTAL", fmt.Sprintf("%d findings", len(findings)), "", "", fmt.Sprintf("$%.2f", total)))
+	return strings.Join(lines, "\n")
+}
+
+// buildAllocationListText lists non-zero group costs by dims, then the
+// chargeback invoice, mirroring the analyze tables. Either part is replaced
+// by a hint when its flag is not set.
+func buildAllocationListText(report *cost.Report, dims []cost.GroupDimension) string {
+	const leftPad = "  "
+	var lines []string
+	if len(dims) == 0 {
+		lines = append(lines, leftPad+"[gray]No grouping; start with --group-by namespace, workload, node or label:<key>[-]")
+	} else {
+		names := make([]string, len(dims))
+		header := ""
+		for i, d := range dims {
+			names[i] = d.String()
+			header += fmt.Sprintf("%-28s ", truncateString(strings.ToUpper(names[i]), 28))
+		}
+		separator := strings.Repeat("-", 29*len(dims)+19)
+		lines = append(lines,
+			leftPad+"[darkcyan]Costs by "+strings.Join(names, ", ")+"[-]",
+			leftPad+fmt.Sprintf("[darkcyan]%s%6s %12s[-]", header, "PODS", "COST"),
+			leftPad+separator,
+		)
+		var total float64
+		rowCount := 0
+		for _, g := range cost.GroupCosts(report, dims) {
+			total += g.Cost
+			if g.Cost <= 0 {
+				continue
+			}
+			row := ""
+			for _, k := range g.Keys {
+				row += fmt.Sprintf("%-28s ", truncateString(k, 28))
+			}
+			lines = append(lines, leftPad+fmt.Sprintf("%s%6d %12s", row, g.Pods, fmt.Sprintf("$%.2f", g.Cost)))
+			rowCount++
+		}
+		if rowCount == 0 {
+			lines = append(lines, leftPad+"[gray]No non-zero group costs[-]")
+		}
+		lines = append(lines, leftPad+separator)
+		lines = append(lines, leftPad+fmt.Sprintf("[green]%-*s%6s %12s[-]", 29*len(dims), "TOTAL", "", fmt.Sprintf("$%.2f", total)))
+	}
+
+	lines = append(lines, "")
+	cb := report.Chargeback
+	if cb == nil {
+		lines = append(lines, leftPad+"[gray]No chargeback; start with --chargeback or set pricing.chargeback.enabled[-]")
+		return strings.Join(lines, "\n")
+	}
+	shared := "none"
+	if len(cb.SharedNamespaces) > 0 {
+		shared = strings.Join(cb.SharedNamespaces, ", ")
+	}
+	separator := strings.Repeat("-", 72)
+	lines = append(lines,
+		leftPad+fmt.Sprintf("[darkcyan]Chargeback (%s split of shared cost)[-]", cb.Method),
+		leftPad+fmt.Sprintf("Shared pool: $%.2f (namespaces %s: $%.2f, idle: $%.2f, control plane: $%.2f, unallocated: $%.2f)",
+			cb.SharedCost, shared, cb.SharedNamespaceCost, cb.IdleCost, cb.ControlPlaneCost, cb.UnallocatedCost),
+	)
+	if cb.OverAllocatedCost > 0 {
+		lines = append(lines, leftPad+fmt.Sprintf("[yellow]Over-allocated:[-] $%.2f (pod prices above node cost, scaled out of direct cost)", cb.OverAllocatedCost))
+	}
+	lines = append(lines,
+		leftPad+fmt.Sprintf("[darkcyan]%-32s %12s %12s %12s[-]", "TENANT", "DIRECT", "SHARED", "TOTAL"),
+		leftPad+separator,
+	)
+	for _, t := range cb.Tenants {
+		lines = append(lines, leftPad+fmt.Sprintf("%-32s %12s %12s %12s",
+			truncateString(t.Namespace, 32),
+			fmt.Sprintf("$%.2f", t.Direct),
+			fmt.Sprintf("$%.2f", t.Shared),
+			fmt.Sprintf("$%.2f", t.Total),
+		))
+	}
+	if len(cb.Tenants) == 0 {
+		lines = append(lines, leftPad+"[gray]No tenant namespaces to charge back[-]")
+	}
+	lines = append(lines, leftPad+separator)
+	lines = append(lines, leftPad+fmt.Sprintf("[green]%-32s %12s %12s %12s[-]", "TOTAL", "", "", fmt.Sprintf("$%.2f", cb.TotalCost)))
+	return strings.Join(lines, "\n")
+}
+
+func severityColor(s cost.Severity) string {
+	switch s {
+	case cost.SeverityHigh:
+		return "red"
+	case cost.SeverityMedium:
+		return "yellow"
+	default:
+		return "gray"
+	}
+}
+
+func budgetColor(s cost.BudgetStatus) string {
+	switch s {
+	case cost.BudgetCritical:
+		return "red"
+	case cost.BudgetWarn:
+		return "yellow"
+	default:
+		return "green"
+	}
+}
+
 func buildPodDetailText(pod PodInfo, freshness StatsFreshness) string {
 	lines := []string{
 		fmt.Sprintf("  Pod:        [white]%s[-]", pod.Name),
 		fmt.Sprintf("  Namespace:  [white]%s[-]", pod.Namespace),
-		fmt.Sprintf("  CPU Req:    [white]%s[-]", pod.CPU),
-		fmt.Sprintf("  Mem Req:    [white]%s[-]", pod.Memory),
+		fmt.Sprintf("  Workload:   [white]%s[-]", pod.Workload),
+		fmt.Sprintf("  Basis:      [white]%s[-]", pod.Basis),
+		fmt.Sprintf("  CPU:        [white]%s[-]", pod.CPU),
+		fmt.Sprintf("  Memory:     [white]%s[-]", pod.Memory),
 		fmt.Sprintf("  Monthly:    [white]$%.2f[-]", pod.Cost),
 		"",
+		"  [darkcyan]Effective Request Breakdown[-]",
+		fmt.Sprintf("  Containers: %s", formatRequests(pod.Requests.Containers)),
+		fmt.Sprintf("  Sidecars:   %s", formatRequests(pod.Requests.Sidecars)),
+		fmt.Sprintf("  Init Peak:  %s", formatRequests(pod.Requests.InitPeak)),
+		fmt.Sprintf("  Overhead:   %s", formatRequests(pod.Requests.Overhead)),
+		"",
 		"  [darkcyan]Prometheus Data Freshness[-]",
 	}
 
@@ -721,6 +1123,13 @@ func buildPodDetailText(pod PodInfo, freshness StatsFreshness) string {
 	return strings.Join(lines, "\n")
 }
 
+func formatRequests(rl corev1.ResourceList) string {
+	if len(rl) == 0 {
+		return "[gray]-[-]"
+	}
+	return fmt.Sprintf("[white]%s[-] cpu / [white]%s[-] mem", rl.Cpu().String(), rl.Memory().String())
+}
+
 func freshnessConfidence(observed time.Duration) (string, string) {
 	switch {
 	case observed < 30*time.Minute:
@@ -749,14 +1158,16 @@ func formatShortDuration(d time.Duration) string {
 	return fmt.Sprintf("%dh%dm", h, m)
 }
 
-func getNamespaces(pods []PodInfo) []string {
-	seen := make(map[string]bool)
-	var namespaces []string
-	for _, pod := range pods {
-		if !seen[pod.Namespace] {
-			seen[pod.Namespace] = true
-			namespaces = append(namespaces, pod.Namespace)
+// namespaceNames lists the namespaces in rollup, including those whose cost
+// is only volumes or load balancers. The idle bucket is left out; it has its
+// own line in the cost breakdown.
+func namespaceNames(rollup []cost.NamespaceCost) []string {
+	namespaces := make([]string, 0, len(rollup))
+	for _, ns := range rollup {
+		if ns.Name == cost.IdleNamespace {
+			continue
 		}
+		namespaces = append(namespaces, ns.Name)
 	}
 	return namespaces
 }
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/newman-bot/kfin/pkg/cost"
+	"github.com/rivo/tview"
+)
+
+// FleetData is the input of the fleet dashboard. Clusters holds one
+// ReportData per cluster, in the order of Fleet.Clusters.
+type FleetData struct {
+	Fleet    *cost.Fleet
+	Clusters []ReportData
+}
+
+const pageClusters = "clusters"
+
+// ShowFleet opens the cluster switcher: per-cluster totals and the costliest
+// namespaces across the fleet. Enter opens a cluster's dashboard and [7]
+// comes back.
+func ShowFleet(data FleetData) {
+	app := tview.NewApplication()
+	root := tview.NewPages()
+	cyan := tcell.ColorDarkCyan
+	fleet := data.Fleet
+
+	header := tview.NewTextView().SetDynamicColors(true)
+	header.SetBackgroundColor(tcell.ColorBlack)
+	header.SetText(fmt.Sprintf(
+		" [green]kFin fleet[-] | Clusters:%d | Nodes:%d | Pods:%d | Monthly:$%.2f | Idle:$%.2f",
+		len(fleet.Clusters), fleet.Nodes, fleet.Pods, fleet.TotalCost, fleet.IdleCost,
+	))
+
+	clusters := tview.NewTable().SetBorders(false)
+	clusters.SetSelectable(true, false)
+	clusters.SetSelectedStyle(tcell.StyleDefault.Background(cyan).Foreground(tcell.ColorBlack))
+	clusters.SetBorder(true).SetTitle(" Clusters ").SetTitleColor(cyan)
+	for i, h := range []string{"CONTEXT", "CLUSTER", "DIST", "NODES", "PODS", "IDLE", "MONTHLY"} {
+		clusters.SetCell(0, i, tview.NewTableCell(h).SetTextColor(cyan).SetSelectable(false))
+	}
+	for i, c := range fleet.Clusters {
+		r := c.Report
+		clusters.SetCell(i+1, 0, tview.NewTableCell(truncateString(c.Context, 32)))
+		clusters.SetCell(i+1, 1, tview.NewTableCell(truncateString(c.Cluster, 32)))
+		clusters.SetCell(i+1, 2, tview.NewTableCell(r.Distribution.String()))
+		clusters.SetCell(i+1, 3, tview.NewTableCell(fmt.Sprintf("%d", len(r.Nodes))).SetAlign(tview.AlignRight))
+		clusters.SetCell(i+1, 4, tview.NewTableCell(fmt.Sprintf("%d", len(r.Pods))).SetAlign(tview.AlignRight))
+		clusters.SetCell(i+1, 5, tview.NewTableCell(fmt.Sprintf("$%.2f", r.IdleCost)).SetAlign(tview.AlignRight))
+		clusters.SetCell(i+1, 6, tview.NewTableCell(fmt.Sprintf("%s $%.2f", costBadge(r.TotalCost), r.TotalCost)).SetAlign(tview.AlignRight))
+	}
+	if len(fleet.Clusters) > 0 {
+		clusters.Select(1, 0)
+	}
+
+	namespaces := tview.NewTextView().SetDynamicColors(true)
+	namespaces.SetBorder(true).SetTitle(" Top Namespaces Across Clusters ").SetTitleColor(cyan)
+	namespaces.SetText(buildFleetNamespacesText(fleet.Namespaces, 20))
+
+	footer := tview.NewTextView().SetDynamicColors(true)
+	footer.SetBackgroundColor(tcell.ColorBlack)
+	footer.SetText(" [darkcyan]CLUSTERS[-]  |  Up/Down: Select  Enter: Open cluster  [7]: Back to clusters  q: Quit ")
+
+	switcher := tview.NewFlex().SetDirection(tview.FlexRow)
+	switcher.AddItem(header, 1, 0, false)
+	switcher.AddItem(clusters, len(fleet.Clusters)+3, 0, true)
+	switcher.AddItem(namespaces, 0, 1, false)
+	switcher.AddItem(footer, 1, 0, false)
+	switcher.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'q' {
+			app.Stop()
+			return nil
+		}
+		return event
+	})
+	root.AddPage(pageClusters, switcher, true, true)
+
+	showClusters := func() {
+		root.SwitchToPage(pageClusters)
+	}
+	built := make(map[int]bool)
+	clusters.SetSelectedFunc(func(row, _ int) {
+		idx := row - 1
+		if idx < 0 || idx >= len(data.Clusters) {
+			return
+		}
+		name := fmt.Sprintf("cluster-%d", idx)
+		if !built[idx] {
+			root.AddPage(name, newDashboard(app, data.Clusters[idx], showClusters), true, false)
+			built[idx] = true
+		}
+		root.SwitchToPage(name)
+	})
+
+	if err := app.SetRoot(root, true).Run(); err != nil {
+		fmt.Printf("Error running TUI: %v\n", err)
+	}
+}
+
+func buildFleetNamespacesText(namespaces []cost.FleetNamespaceCost, limit int) string {
+	const leftPad = " "
+	lines := []string{leftPad + fmt.Sprintf("[darkcyan]%-24s %-32s %6s %12s[-]", "CONTEXT", "NAMESPACE", "PODS", "COST")}
+	shown := 0
+	for _, ns := range namespaces {
+		if ns.Cost <= 0 || ns.Name == cost.IdleNamespace {
+			continue
+		}
+		if shown == limit {
+			break
+		}
+		lines = append(lines, leftPad+fmt.Sprintf("%-24s %-32s %6d %12s",
+			truncateString(ns.Context, 24),
+			truncateString(ns.Name, 32),
+			ns.Pods,
+			fmt.Sprintf("$%.2f", ns.Cost),
+		))
+		shown++
+	}
+	if shown == 0 {
+		lines = append(lines, leftPad+"[gray]No non-zero cost namespaces[-]")
+	}
+	return strings.Join(lines, "\n")
+}